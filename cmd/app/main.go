@@ -9,22 +9,36 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
 	"github.com/alextotalk/feline-intelligence/internal/config"
 	"github.com/alextotalk/feline-intelligence/internal/delivery/handlers"
+	"github.com/alextotalk/feline-intelligence/internal/delivery/middlewares"
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/infrastructure/auth"
 	"github.com/alextotalk/feline-intelligence/internal/infrastructure/catapi"
+	"github.com/alextotalk/feline-intelligence/internal/infrastructure/eventsink"
+	"github.com/alextotalk/feline-intelligence/internal/infrastructure/metrics"
+	"github.com/alextotalk/feline-intelligence/internal/infrastructure/ratelimit"
 	"github.com/alextotalk/feline-intelligence/internal/infrastructure/repository"
+	"github.com/alextotalk/feline-intelligence/internal/infrastructure/telemetry"
+	"github.com/alextotalk/feline-intelligence/internal/infrastructure/webhook"
 	"github.com/alextotalk/feline-intelligence/internal/lib/logger/handlers/slogpretty"
 	"github.com/alextotalk/feline-intelligence/internal/lib/logger/sl"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
 	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
 	"github.com/alextotalk/feline-intelligence/internal/usecase"
+	"github.com/alextotalk/feline-intelligence/migrations"
 
 	_ "github.com/alextotalk/feline-intelligence/docs"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/swaggo/echo-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 )
 
 const (
@@ -33,18 +47,50 @@ const (
 	envProd  = "prod"
 )
 
+// Set at build time with -ldflags "-X main.commit=<sha> -X main.buildTime=<RFC 3339>".
+var (
+	commit    = "unknown"
+	buildTime = "unknown"
+)
+
 // @title Feline Intelligence API
 // @version 1.0
 // @description APIs to manage spy cats, missions and goals.
 // @host localhost:8080
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT or API key as "Bearer <token>"
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
 	cfg, err := config.LoadConfig("config/local.yaml")
 	if err != nil {
 		log.Fatalf("cannot read config: %v", err)
 	}
 
-	logger := InitLogger(cfg.App.Env)
+	// Records logged with a request context carry its trace_id and span_id.
+	logger := slog.New(tracing.NewLogHandler(InitLogger(cfg.App.Env).Handler()))
+	slog.SetDefault(logger)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(cfg, logger, os.Args[2:]))
+	}
+
+	shutdownTracing, err := telemetry.SetupTracing(context.Background(), telemetry.TracingConfig{
+		ServiceName:    cfg.App.Name,
+		ServiceVersion: cfg.App.Version,
+		Exporter:       cfg.Tracing.Exporter,
+		Endpoint:       cfg.Tracing.Endpoint,
+		Insecure:       cfg.Tracing.Insecure,
+		SampleRatio:    cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logger.Error("Failed to initialize tracing", sl.Err(err))
+		os.Exit(1)
+	}
 	logger.Info("Starting application", "app", cfg.App.Name, "env", cfg.App.Env)
 
 	db, err := pg.NewPostgres(cfg)
@@ -53,23 +99,196 @@ func main() {
 		os.Exit(1)
 	}
 	logger.Info("Successfully connected to Postgres", "host", cfg.Database.Host)
+	// Background jobs work across agencies, with row-level security they get a pool of their own.
+	platformDB := db
+	if cfg.Database.RowLevelSecurity {
+		if err := pg.CheckRowLevelSecurity(context.Background(), db); err != nil {
+			logger.Error("Row-level security would not be enforced", sl.Err(err))
+			os.Exit(1)
+		}
+		if platformDB, err = pg.NewPlatformPostgres(cfg); err != nil {
+			logger.Error("Failed to initialize Postgres", sl.Err(err))
+			os.Exit(1)
+		}
+	}
 
-	catAPI := catapi.NewCatAPI("https://api.thecatapi.com", "") // наприклад, cfg.App.TheCatAPIKey
+	migrator, err := pg.NewMigrator(db, migrations.FS, logger)
+	if err != nil {
+		logger.Error("Failed to read migrations", sl.Err(err))
+		os.Exit(1)
+	}
+	if cfg.Database.MigrateOnStartup {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			logger.Error("Failed to apply migrations", sl.Err(err))
+			os.Exit(1)
+		}
+		logger.Info("Database schema is up to date", "version", migrator.Latest(), "applied", applied)
+	}
 
 	catRepo := repository.NewCatPgRepository(db)
 	missionRepo := repository.NewMissionPgRepository(db)
 	targetRepo := repository.NewTargetPgRepository(db)
+	auditRepo := repository.NewAuditPgRepository(db)
+	salaryRepo := repository.NewSalaryHistoryPgRepository(db)
+	reportRepo := repository.NewReportPgRepository(db)
+	statsRepo := repository.NewStatsPgRepository(db)
+	assignmentRepo := repository.NewAssignmentPgRepository(db)
+	exportRepo := repository.NewExportPgRepository(db)
+	idempotencyRepo := repository.NewIdempotencyPgRepository(db)
+	outboxRepo := repository.NewOutboxPgRepository(db)
+	webhookRepo := repository.NewWebhookPgRepository(db)
+	eventStreamRepo := repository.NewEventStreamPgRepository(db)
+	apiKeyRepo := repository.NewAPIKeyPgRepository(db)
+	agencyRepo := repository.NewAgencyPgRepository(db)
+	transactor := pg.NewTransactor(db, cfg.Database.RowLevelSecurity)
+	platformOutboxRepo := repository.NewOutboxPgRepository(platformDB)
+	platformWebhookRepo := repository.NewWebhookPgRepository(platformDB)
+	platformTransactor := pg.NewTransactor(platformDB, cfg.Database.RowLevelSecurity)
+
+	var (
+		appMetrics     *metrics.Metrics
+		catAPIObserver catapi.Observer
+	)
+	if cfg.Metrics.Enabled {
+		appMetrics = metrics.New(db, statsRepo, logger)
+		catAPIObserver = appMetrics.ObserveCatAPI
+	}
+	catAPI := catapi.NewCatAPI("https://api.thecatapi.com", "", catAPIObserver) // наприклад, cfg.App.TheCatAPIKey
+
+	healthUC := usecase.NewHealthUsecase(repository.NewHealthPgRepository(db), catAPI, usecase.HealthConfig{
+		Timeout:       cfg.Health.Timeout,
+		SchemaVersion: migrator.Latest(),
+		CheckCatAPI:   cfg.Health.CheckCatAPI,
+		Build: model.BuildInfo{
+			Name:      cfg.App.Name,
+			Version:   cfg.App.Version,
+			Commit:    commit,
+			BuildTime: buildTime,
+		},
+	})
+
+	auditUC := usecase.NewAuditUsecase(auditRepo)
+	eventUC := usecase.NewEventUsecase(outboxRepo)
+	catUC := usecase.NewCatUsecase(catRepo, salaryRepo, assignmentRepo, transactor, catAPI, auditUC, eventUC, usecase.SalaryPolicy{
+		ApprovalThresholdPercent: cfg.Salary.ApprovalThresholdPercent,
+		Currencies:               cfg.Salary.Currencies,
+	})
+	missionUC := usecase.NewMissionUsecase(missionRepo, targetRepo, catRepo, assignmentRepo, transactor, auditUC, eventUC)
+	reportUC := usecase.NewReportUsecase(reportRepo)
+	statsUC := usecase.NewStatsUsecase(statsRepo)
+	matchingUC := usecase.NewMatchingUsecase(missionRepo, catRepo, statsRepo, catAPI, missionUC)
+	importUC := usecase.NewImportUsecase(catRepo, agencyRepo, transactor, catAPI, catUC, missionUC)
+	exportUC := usecase.NewExportUsecase(exportRepo)
+	agentUC := usecase.NewAgentUsecase(catUC, missionUC)
+	idempotencyUC := usecase.NewIdempotencyUsecase(idempotencyRepo, cfg.Idempotency.TTL, cfg.Idempotency.LockTimeout)
+	idempotencyPurger := usecase.NewIdempotencyUsecase(repository.NewIdempotencyPgRepository(platformDB),
+		cfg.Idempotency.TTL, cfg.Idempotency.LockTimeout)
+
+	dispatcher := usecase.NewEventDispatcher(platformOutboxRepo, platformTransactor, []domain.EventSink{
+		eventsink.NewLogSink(logger),
+		eventsink.NewWebhookSink(platformWebhookRepo),
+	}, usecase.DispatcherConfig{
+		PollInterval: cfg.Events.PollInterval,
+		BatchSize:    cfg.Events.BatchSize,
+		MaxBackoff:   cfg.Events.MaxBackoff,
+	}, logger)
+
+	webhookUC := usecase.NewWebhookUsecase(webhookRepo)
+	webhookDeliverer := usecase.NewWebhookDeliverer(platformWebhookRepo, platformTransactor,
+		webhook.NewSender(&http.Client{Timeout: cfg.Webhooks.Timeout}),
+		usecase.WebhookDeliveryConfig{
+			PollInterval: cfg.Webhooks.PollInterval,
+			MaxAttempts:  cfg.Webhooks.MaxAttempts,
+			MaxBackoff:   cfg.Webhooks.MaxBackoff,
+			DisableAfter: cfg.Webhooks.DisableAfter,
+			Lease:        cfg.Webhooks.Lease,
+		}, logger)
+	eventBroker := usecase.NewEventBroker(eventStreamRepo, pg.NewEventListener(pg.DSN(cfg), logger),
+		cfg.Stream.BufferSize, logger)
 
-	catUC := usecase.NewCatUsecase(catRepo, catAPI)
-	missionUC := usecase.NewMissionUsecase(missionRepo, targetRepo, catRepo)
+	var tokenVerifier domain.TokenVerifier
+	if cfg.Auth.Enabled {
+		tokenVerifier, err = auth.NewJWTVerifier(auth.JWTConfig{
+			HS256Secret:        cfg.Auth.JWT.HS256Secret,
+			RS256PublicKeyFile: cfg.Auth.JWT.RS256PublicKeyFile,
+			Issuer:             cfg.Auth.JWT.Issuer,
+			Audience:           cfg.Auth.JWT.Audience,
+		})
+		if err != nil {
+			logger.Error("Failed to initialize JWT verifier", sl.Err(err))
+			os.Exit(1)
+		}
+	}
+	authUC := usecase.NewAuthUsecase(apiKeyRepo, catRepo, agencyRepo, tokenVerifier)
+	agencyUC := usecase.NewAgencyUsecase(agencyRepo)
 
 	e := echo.New()
-	e.Use(middleware.Logger())
-	e.Use(middleware.Recover())
+	e.Use(middleware.RequestID())
+	e.Use(otelecho.Middleware(cfg.App.Name))
+	e.Use(middlewares.RequestLogger(logger))
+	if appMetrics != nil {
+		e.Use(middlewares.Metrics(appMetrics))
+	}
+	e.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
+		LogErrorFunc: func(c echo.Context, err error, stack []byte) error {
+			ctx := c.Request().Context()
+			requestctx.Logger(ctx).ErrorContext(ctx, "Recovered from panic", sl.Err(err), "stack", string(stack))
+			return err
+		},
+	}))
+	e.Use(middlewares.RequestContext())
+	publicPaths := append([]string{"/swagger/*"}, handlers.HealthPaths...)
+	if appMetrics != nil && cfg.Metrics.Address == "" {
+		// Prometheus scrapes without credentials, use a separate metrics address to keep them private.
+		e.GET("/metrics", echo.WrapHandler(appMetrics.Handler()))
+		publicPaths = append(publicPaths, "/metrics")
+	}
+	// Probes and scrapes are not rate limited, a throttled probe would restart a healthy replica.
+	rateLimitExempt := append(slices.Clone(handlers.HealthPaths), "/metrics")
+	var rateLimitStore domain.RateLimitStore
+	if cfg.RateLimit.Enabled {
+		switch cfg.RateLimit.Store {
+		case "", "memory":
+			rateLimitStore = ratelimit.NewMemoryStore()
+		case "postgres":
+			rateLimitStore = repository.NewRateLimitPgRepository(db)
+		default:
+			logger.Error("Unknown rate limit store", "store", cfg.RateLimit.Store)
+			os.Exit(1)
+		}
+		e.Use(middlewares.RateLimitIP(usecase.NewRateLimitUsecase(rateLimitStore, usecase.RateLimitPolicy{
+			Default: rateLimit(cfg.RateLimit.PerIP),
+		}), rateLimitExempt...))
+	}
+	if cfg.Auth.Enabled {
+		e.Use(middlewares.Authenticate(authUC, publicPaths...))
+	} else {
+		logger.Warn("Authentication is disabled, the API is open to anyone")
+	}
+	e.Use(middlewares.Tenant(agencyUC))
+	e.Use(middlewares.TenantConnection(transactor.ScopeRequest))
+	if rateLimitStore != nil {
+		e.Use(middlewares.RateLimit(usecase.NewRateLimitUsecase(rateLimitStore, rateLimitPolicy(cfg)), rateLimitExempt...))
+	}
+	// Clients retry these on flaky networks. Imports are excluded, their bodies are too large to store.
+	e.Use(middlewares.Idempotency(idempotencyUC, cfg.Idempotency.MaxBodySize, "/cats", "/missions", "/missions/:id/targets"))
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
 	handlers.NewCatHandler(e, catUC)
 	handlers.NewMissionHandler(e, missionUC)
+	handlers.NewAuditHandler(e, auditUC)
+	handlers.NewReportHandler(e, reportUC)
+	handlers.NewStatsHandler(e, statsUC)
+	handlers.NewMatchingHandler(e, matchingUC)
+	handlers.NewImportHandler(e, importUC)
+	handlers.NewExportHandler(e, exportUC)
+	handlers.NewWebhookHandler(e, webhookUC)
+	handlers.NewEventStreamHandler(e, eventBroker, cfg.Stream.Heartbeat)
+	handlers.NewAuthHandler(e, authUC)
+	handlers.NewMeHandler(e, agentUC)
+	handlers.NewAgencyHandler(e, agencyUC)
+	handlers.NewHealthHandler(e, healthUC)
 
 	go func() {
 		address := fmt.Sprintf(":%d", cfg.Server.Port)
@@ -80,26 +299,101 @@ func main() {
 		}
 	}()
 
+	var metricsServer *http.Server
+	if appMetrics != nil && cfg.Metrics.Address != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", appMetrics.Handler())
+		metricsServer = &http.Server{Addr: cfg.Metrics.Address, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			logger.Info("Starting metrics server", "address", cfg.Metrics.Address)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Error starting metrics server", sl.Err(err))
+				os.Exit(1)
+			}
+		}()
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	go purgeIdempotencyKeys(ctx, idempotencyPurger, logger)
+	go dispatcher.Run(ctx)
+	go webhookDeliverer.Run(ctx)
+	go eventBroker.Run(ctx)
+
 	<-ctx.Done()
 	logger.Info("Shutdown signal received")
 
+	healthUC.ShutDown()
+	if cfg.Health.ShutdownDelay > 0 {
+		logger.Info("Waiting for load balancers to notice the shutdown", "delay", cfg.Health.ShutdownDelay)
+		time.Sleep(cfg.Health.ShutdownDelay)
+	}
+
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := e.Shutdown(shutdownCtx); err != nil {
 		logger.Error("Error shutting down server", sl.Err(err))
 	}
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Error shutting down metrics server", sl.Err(err))
+		}
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("Error flushing traces", sl.Err(err))
+	}
 
 	if err := db.Close(); err != nil {
 		logger.Error("Error closing database connection", sl.Err(err))
 	}
+	if platformDB != db {
+		if err := platformDB.Close(); err != nil {
+			logger.Error("Error closing database connection", sl.Err(err))
+		}
+	}
 
 	logger.Info("Application shut down gracefully.")
 }
 
+// rateLimitPolicy converts the configured per-client rate limits.
+func rateLimitPolicy(cfg *config.Config) usecase.RateLimitPolicy {
+	policy := usecase.RateLimitPolicy{
+		Default: rateLimit(cfg.RateLimit.Default),
+		Routes:  make(map[string]model.RateLimit, len(cfg.RateLimit.Routes)),
+	}
+	for route, r := range cfg.RateLimit.Routes {
+		policy.Routes[route] = rateLimit(r)
+	}
+	return policy
+}
+
+// rateLimit converts a configured rate limit.
+func rateLimit(r config.RateLimitRule) model.RateLimit {
+	return model.RateLimit{Requests: r.Requests, Per: r.Per, Burst: r.Burst}
+}
+
+// purgeIdempotencyKeys periodically deletes expired idempotency keys until ctx is done.
+func purgeIdempotencyKeys(ctx context.Context, uc usecase.IdempotencyUsecase, logger *slog.Logger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := uc.PurgeExpired(ctx)
+			if err != nil {
+				logger.Error("Failed to purge idempotency keys", sl.Err(err))
+				continue
+			}
+			logger.Debug("Purged idempotency keys", "count", n)
+		}
+	}
+}
+
 func InitLogger(env string) *slog.Logger {
 	switch env {
 	case envLocal:
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/alextotalk/feline-intelligence/internal/config"
+	"github.com/alextotalk/feline-intelligence/internal/lib/logger/sl"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
+	"github.com/alextotalk/feline-intelligence/migrations"
+)
+
+const migrateUsage = `usage: app migrate <command>
+
+commands:
+  up        apply all pending migrations
+  down [N]  revert the last N migrations (1 by default)
+  status    list the migrations and whether they are applied
+  version   print the schema version of the database`
+
+// runMigrate runs the "migrate" subcommand with the embedded migrations and returns the exit code.
+func runMigrate(cfg *config.Config, logger *slog.Logger, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	db, err := pg.NewPostgres(cfg)
+	if err != nil {
+		logger.Error("Failed to initialize Postgres", sl.Err(err))
+		return 1
+	}
+	defer db.Close()
+
+	migrator, err := pg.NewMigrator(db, migrations.FS, logger)
+	if err != nil {
+		logger.Error("Failed to read migrations", sl.Err(err))
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			logger.Error("Failed to apply migrations", sl.Err(err))
+			return 1
+		}
+		fmt.Printf("applied %d migration(s)\n", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				fmt.Fprintln(os.Stderr, "down expects a positive number of migrations")
+				return 2
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			logger.Error("Failed to revert migrations", sl.Err(err))
+			return 1
+		}
+		fmt.Printf("reverted %d migration(s)\n", reverted)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			logger.Error("Failed to read the migration status", sl.Err(err))
+			return 1
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%03d  %-8s %s\n", s.Version, state, s.Name)
+		}
+	case "version":
+		version, dirty, err := migrator.Version(ctx)
+		if err != nil {
+			logger.Error("Failed to read the schema version", sl.Err(err))
+			return 1
+		}
+		fmt.Printf("version %d (latest %d)", version, migrator.Latest())
+		if dirty {
+			fmt.Print(", dirty")
+		}
+		fmt.Println()
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
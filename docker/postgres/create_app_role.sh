@@ -0,0 +1,16 @@
+#!/bin/sh
+# Creates the role the application connects as. Postgres skips row-level security for
+# superusers like POSTGRES_USER, so the application gets a role without SUPERUSER and
+# BYPASSRLS that may only read and write the tables created by the migrations.
+# Runs once, when the data directory is initialized.
+set -e
+
+psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" <<EOSQL
+CREATE ROLE "$APP_DB_USER" LOGIN PASSWORD '$APP_DB_PASSWORD' NOSUPERUSER NOBYPASSRLS NOCREATEROLE NOCREATEDB;
+GRANT CONNECT ON DATABASE "$POSTGRES_DB" TO "$APP_DB_USER";
+GRANT USAGE ON SCHEMA public TO "$APP_DB_USER";
+ALTER DEFAULT PRIVILEGES FOR ROLE "$POSTGRES_USER" IN SCHEMA public
+    GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO "$APP_DB_USER";
+ALTER DEFAULT PRIVILEGES FOR ROLE "$POSTGRES_USER" IN SCHEMA public
+    GRANT USAGE, SELECT ON SEQUENCES TO "$APP_DB_USER";
+EOSQL
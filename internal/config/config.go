@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 type Config struct {
@@ -29,7 +30,127 @@ type Config struct {
 		Password string `yaml:"password"`
 		DBName   string `yaml:"dbname"`
 		SSLMode  string `yaml:"sslmode"`
+		// RowLevelSecurity additionally has Postgres restrict the queries of a request to the
+		// rows of its agency. Queries are scoped by agency either way. Superusers and roles with
+		// BYPASSRLS skip the policies, the application refuses to start as one.
+		RowLevelSecurity bool `yaml:"row_level_security"`
+		// MigrateOnStartup applies pending migrations before serving. Replicas starting
+		// together wait for each other, so the migrations are applied once.
+		MigrateOnStartup bool `yaml:"migrate_on_startup" env:"DATABASE_MIGRATE_ON_STARTUP"`
 	} `yaml:"database"`
+
+	Salary struct {
+		// ApprovalThresholdPercent is the raise (in percent) above which a salary change
+		// has to be approved. Zero disables the approval workflow.
+		ApprovalThresholdPercent float64 `yaml:"approval_threshold_percent"`
+		// Currencies lists allowed salary currencies (ISO 4217), the first one is the default.
+		Currencies []string `yaml:"currencies"`
+	} `yaml:"salary"`
+
+	Idempotency struct {
+		// TTL is how long responses are kept for replay on retries.
+		TTL time.Duration `yaml:"ttl"`
+		// LockTimeout is how long a request holds its key. Retries take over the key of a request
+		// that crashed after that, so it must exceed the longest request.
+		LockTimeout time.Duration `yaml:"lock_timeout"`
+		// MaxBodySize is the largest body, in bytes, of a request with an idempotency key.
+		MaxBodySize int64 `yaml:"max_body_size"`
+	} `yaml:"idempotency"`
+
+	Events struct {
+		// PollInterval is how often the outbox is checked for new events.
+		PollInterval time.Duration `yaml:"poll_interval"`
+		// BatchSize is the number of events delivered per outbox transaction.
+		BatchSize int `yaml:"batch_size"`
+		// MaxBackoff caps the delay between delivery attempts of a failing event.
+		MaxBackoff time.Duration `yaml:"max_backoff"`
+	} `yaml:"events"`
+
+	Webhooks struct {
+		// Timeout limits a single delivery request.
+		Timeout      time.Duration `yaml:"timeout"`
+		PollInterval time.Duration `yaml:"poll_interval"`
+		// MaxAttempts is the number of attempts after which a delivery is given up.
+		MaxAttempts int           `yaml:"max_attempts"`
+		MaxBackoff  time.Duration `yaml:"max_backoff"`
+		// DisableAfter is the number of consecutive failed attempts that disables a subscription.
+		DisableAfter int `yaml:"disable_after"`
+		// Lease is how long a replica reserves the deliveries it claimed, they are sent again
+		// by another replica when the outcome is not recorded by then.
+		Lease time.Duration `yaml:"lease"`
+	} `yaml:"webhooks"`
+
+	Stream struct {
+		// BufferSize is the number of recent events replayed to clients reconnecting with Last-Event-ID.
+		BufferSize int `yaml:"buffer_size"`
+		// Heartbeat is how often an idle stream gets a comment line to keep the connection open.
+		Heartbeat time.Duration `yaml:"heartbeat"`
+	} `yaml:"stream"`
+
+	Auth struct {
+		// Enabled requires an API key or a bearer token on every request except the docs.
+		Enabled bool `yaml:"enabled"`
+		// JWT verifies bearer tokens. Tokens carry the caller roles in the "roles" claim,
+		// for agents the cat they act as in "cat_id" and the agency of the caller in "tenant_id".
+		JWT struct {
+			// HS256Secret and RS256PublicKeyFile (PEM) verify bearer tokens, one of them is required.
+			HS256Secret        string `yaml:"hs256_secret" env:"AUTH_JWT_HS256_SECRET"`
+			RS256PublicKeyFile string `yaml:"rs256_public_key_file"`
+			// Issuer and Audience, when set, must match the iss and aud claims of the tokens.
+			Issuer   string `yaml:"issuer"`
+			Audience string `yaml:"audience"`
+		} `yaml:"jwt"`
+	} `yaml:"auth"`
+
+	RateLimit struct {
+		// Enabled limits the requests of every client: API key, token subject or IP address.
+		Enabled bool `yaml:"enabled"`
+		// Store keeps the counters, "memory" per replica or "postgres" shared by all replicas.
+		Store string `yaml:"store"`
+		// PerIP limits every IP address before the credentials are checked. Zero disables it.
+		PerIP   RateLimitRule `yaml:"per_ip"`
+		Default RateLimitRule `yaml:"default"`
+		// Routes overrides the limit of routes, keyed by method and route pattern, e.g. "POST /cats".
+		Routes map[string]RateLimitRule `yaml:"routes"`
+	} `yaml:"rate_limit"`
+
+	Metrics struct {
+		// Enabled exposes Prometheus metrics on /metrics.
+		Enabled bool `yaml:"enabled"`
+		// Address serves the metrics on a separate listener, e.g. ":9090". When empty they are
+		// served on the API port, without authentication.
+		Address string `yaml:"address"`
+	} `yaml:"metrics"`
+
+	Tracing struct {
+		// Exporter sends spans to an OTLP/HTTP collector ("otlp") or to stdout ("stdout").
+		// Tracing is disabled when it is empty, traceparent headers are still propagated.
+		Exporter string `yaml:"exporter"`
+		// Endpoint is the host:port of the collector, OTEL_EXPORTER_OTLP_ENDPOINT applies when empty.
+		Endpoint string `yaml:"endpoint"`
+		// Insecure sends spans over plain HTTP.
+		Insecure bool `yaml:"insecure"`
+		// SampleRatio is the share of new traces that are recorded, from 0 to 1.
+		SampleRatio float64 `yaml:"sample_ratio"`
+	} `yaml:"tracing"`
+
+	Health struct {
+		// Timeout limits every readiness check.
+		Timeout time.Duration `yaml:"timeout"`
+		// CheckCatAPI makes readiness depend on thecatapi being reachable.
+		CheckCatAPI bool `yaml:"check_catapi"`
+		// ShutdownDelay keeps serving for a while after readiness started failing on shutdown,
+		// so load balancers stop routing requests before the server stops.
+		ShutdownDelay time.Duration `yaml:"shutdown_delay"`
+	} `yaml:"health"`
+}
+
+// RateLimitRule allows Requests per Per on average with bursts of up to Burst requests
+// (Requests when zero).
+type RateLimitRule struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"`
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+type AgencyHandler struct {
+	agencyUC usecase.AgencyUsecase
+}
+
+func NewAgencyHandler(e *echo.Echo, agencyUC usecase.AgencyUsecase) {
+	handler := &AgencyHandler{agencyUC: agencyUC}
+
+	e.POST("/agencies", handler.CreateAgency)
+	e.GET("/agencies", handler.ListAgencies)
+	e.GET("/agencies/current", handler.CurrentAgency)
+	e.GET("/agencies/:id", handler.GetAgency)
+	e.PUT("/agencies/:id", handler.UpdateAgency)
+}
+
+type agencyRequest struct {
+	Name string `json:"name" example:"Northern agency"`
+	// MaxTargetsPerMission defaults to 3 for new agencies.
+	MaxTargetsPerMission int `json:"max_targets_per_mission" example:"3"`
+}
+
+// CreateAgency Creates an agency.
+// @Summary Create an agency
+// @Description Creates an agency (tenant) with its own cats and missions. Only for callers not bound to an agency.
+// @Tags agencies
+// @Accept json
+// @Produce json
+// @Param agency body agencyRequest true "Agency"
+// @Success 201 {object} model.Agency
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 409 {object} map[string]string "Agency name is taken"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /agencies [post]
+func (h *AgencyHandler) CreateAgency(c echo.Context) error {
+	var req agencyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	agency := &model.Agency{Name: req.Name, MaxTargetsPerMission: req.MaxTargetsPerMission}
+	if err := h.agencyUC.CreateAgency(c.Request().Context(), agency); err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusCreated, agency)
+}
+
+// ListAgencies Returns agencies.
+// @Summary List of agencies
+// @Description Gets all agencies. Only for callers not bound to an agency.
+// @Tags agencies
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.Agency
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /agencies [get]
+func (h *AgencyHandler) ListAgencies(c echo.Context) error {
+	agencies, err := h.agencyUC.ListAgencies(c.Request().Context())
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, agencies)
+}
+
+// CurrentAgency Returns the agency of the request.
+// @Summary Current agency
+// @Description Gets the agency the request acts within: the one of the credentials, or of the X-Tenant-ID header, or the default one
+// @Tags agencies
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.Agency
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /agencies/current [get]
+func (h *AgencyHandler) CurrentAgency(c echo.Context) error {
+	agency, err := h.agencyUC.CurrentAgency(c.Request().Context())
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, agency)
+}
+
+// GetAgency Returns an agency.
+// @Summary Get an agency
+// @Description Gets the agency by its ID. Callers bound to an agency only see their own.
+// @Tags agencies
+// @Accept json
+// @Produce json
+// @Param id path int true "ID agency"
+// @Success 200 {object} model.Agency
+// @Failure 404 {object} map[string]string "Agency not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /agencies/{id} [get]
+func (h *AgencyHandler) GetAgency(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	agency, err := h.agencyUC.GetAgency(c.Request().Context(), id)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, agency)
+}
+
+// UpdateAgency Updates an agency.
+// @Summary Update an agency
+// @Description Changes the name and the configuration of the agency. Lowering max_targets_per_mission does not affect existing missions.
+// @Tags agencies
+// @Accept json
+// @Produce json
+// @Param id path int true "ID agency"
+// @Param agency body agencyRequest true "Agency"
+// @Success 200 {object} model.Agency
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 404 {object} map[string]string "Agency not found"
+// @Failure 409 {object} map[string]string "Agency name is taken"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /agencies/{id} [put]
+func (h *AgencyHandler) UpdateAgency(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req agencyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	agency := &model.Agency{ID: id, Name: req.Name, MaxTargetsPerMission: req.MaxTargetsPerMission}
+	if err := h.agencyUC.UpdateAgency(c.Request().Context(), agency); err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, agency)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+type AuditHandler struct {
+	auditUC usecase.AuditUsecase
+}
+
+func NewAuditHandler(e *echo.Echo, auditUC usecase.AuditUsecase) {
+	handler := &AuditHandler{auditUC: auditUC}
+
+	e.GET("/audit", handler.ListEvents)
+}
+
+// ListEvents Returns audit events.
+// @Summary List of audit events
+// @Description Gets audit events of mutating operations, newest first, filtered by entity, actor and time range
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param entity_type query string false "Entity type (cat, mission, target)"
+// @Param entity_id query int false "Entity ID"
+// @Param actor query string false "Actor"
+// @Param from query string false "Start of the time range (RFC3339), inclusive"
+// @Param to query string false "End of the time range (RFC3339), exclusive"
+// @Param limit query int false "Maximum number of events (default 100)"
+// @Success 200 {array} model.AuditEvent
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /audit [get]
+func (h *AuditHandler) ListEvents(c echo.Context) error {
+	filter := model.AuditFilter{
+		EntityType: c.QueryParam("entity_type"),
+		Actor:      c.QueryParam("actor"),
+	}
+
+	var err error
+	if v := c.QueryParam("entity_id"); v != "" {
+		if filter.EntityID, err = strconv.Atoi(v); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid entity_id"})
+		}
+	}
+	if v := c.QueryParam("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid limit"})
+		}
+	}
+	if v := c.QueryParam("from"); v != "" {
+		if filter.From, err = time.Parse(time.RFC3339, v); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid from, expected RFC3339"})
+		}
+	}
+	if v := c.QueryParam("to"); v != "" {
+		if filter.To, err = time.Parse(time.RFC3339, v); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid to, expected RFC3339"})
+		}
+	}
+
+	events, err := h.auditUC.ListEvents(c.Request().Context(), filter)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, events)
+}
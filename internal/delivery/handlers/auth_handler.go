@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+type AuthHandler struct {
+	authUC usecase.AuthUsecase
+}
+
+func NewAuthHandler(e *echo.Echo, authUC usecase.AuthUsecase) {
+	handler := &AuthHandler{authUC: authUC}
+
+	e.POST("/auth/api-keys", handler.IssueAPIKey)
+	e.GET("/auth/api-keys", handler.ListAPIKeys)
+	e.DELETE("/auth/api-keys/:id", handler.RevokeAPIKey)
+}
+
+type apiKeyRequest struct {
+	Name string `json:"name" example:"ops-dashboard"`
+	// Subject is the identity the key authenticates, the name when omitted.
+	Subject string   `json:"subject,omitempty" example:"service:ops-dashboard"`
+	Roles   []string `json:"roles" example:"handler"`
+	// CatID is required for agent keys, it is the cat the agent acts as.
+	CatID *int `json:"cat_id,omitempty" example:"1"`
+	// TenantID binds the key to an agency. Keys issued by callers bound to an agency are
+	// always bound to the same one.
+	TenantID  *int       `json:"tenant_id,omitempty" example:"1"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2024-01-01T00:00:00Z"`
+}
+
+// IssueAPIKey Issues an API key.
+// @Summary Issue an API key
+// @Description Creates an API key for the subject with the given roles (admin, handler, agent, auditor). Agent keys act as the cat of cat_id. Keys bound to an agency (tenant_id) only access its data.
+// @Description The key is only returned here, send it as X-API-Key or as a bearer token.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param key body apiKeyRequest true "API key"
+// @Success 201 {object} model.IssuedAPIKey
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /auth/api-keys [post]
+func (h *AuthHandler) IssueAPIKey(c echo.Context) error {
+	var req apiKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	issued, err := h.authUC.IssueAPIKey(c.Request().Context(), &model.APIKey{
+		Name:      req.Name,
+		Subject:   req.Subject,
+		Roles:     req.Roles,
+		CatID:     req.CatID,
+		TenantID:  req.TenantID,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusCreated, issued)
+}
+
+// ListAPIKeys Returns API keys.
+// @Summary List of API keys
+// @Description Gets all issued API keys including revoked ones, without the keys themselves
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.APIKey
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /auth/api-keys [get]
+func (h *AuthHandler) ListAPIKeys(c echo.Context) error {
+	keys, err := h.authUC.ListAPIKeys(c.Request().Context())
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, keys)
+}
+
+// RevokeAPIKey Revokes an API key.
+// @Summary Revoke an API key
+// @Description Revokes the key, requests with it are rejected from now on
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID API key"
+// @Success 200
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "API key not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /auth/api-keys/{id} [delete]
+func (h *AuthHandler) RevokeAPIKey(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.authUC.RevokeAPIKey(c.Request().Context(), id); err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.NoContent(http.StatusOK)
+}
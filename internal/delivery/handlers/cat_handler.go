@@ -1,9 +1,9 @@
 package handlers
 
 import (
-	"context"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
@@ -22,6 +22,9 @@ func NewCatHandler(e *echo.Echo, catUC usecase.CatUsecase) {
 	e.GET("/cats", handler.ListCats)
 	e.GET("/cats/:id", handler.GetCatByID)
 	e.PUT("/cats/:id/salary", handler.UpdateSalary)
+	e.GET("/cats/:id/salary-history", handler.GetSalaryHistory)
+	e.PUT("/cats/:id/salary-history/:changeID/approve", handler.ApproveSalaryChange)
+	e.PUT("/cats/:id/salary-history/:changeID/reject", handler.RejectSalaryChange)
 	e.DELETE("/cats/:id", handler.DeleteCat)
 }
 
@@ -32,9 +35,16 @@ func NewCatHandler(e *echo.Echo, catUC usecase.CatUsecase) {
 // @Accept json
 // @Produce json
 // @Param cat body model.Cat true "Cat data"
+// @Param Idempotency-Key header string false "Key to safely retry the request, the first response is replayed"
 // @Success 201 {object} model.Cat
 // @Failure 400 {object} map[string]string “Incorrect request”
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 422 {object} map[string]string "Idempotency key was used for a different request"
+// @Failure 413 {object} map[string]string "Body of a request with an idempotency key is too large"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /cats [post]
 func (h *CatHandler) CreateCat(c echo.Context) error {
 	var cat model.Cat
@@ -42,8 +52,8 @@ func (h *CatHandler) CreateCat(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	if err := h.catUC.CreateCat(context.Background(), &cat); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if err := h.catUC.CreateCat(c.Request().Context(), &cat); err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
 	}
 
 	return c.JSON(http.StatusCreated, cat)
@@ -57,11 +67,15 @@ func (h *CatHandler) CreateCat(c echo.Context) error {
 // @Produce json
 // @Success 200 {array} model.Cat
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /cats [get]
 func (h *CatHandler) ListCats(c echo.Context) error {
-	cats, err := h.catUC.ListCats(context.Background())
+	cats, err := h.catUC.ListCats(c.Request().Context())
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return errorJSON(c, err, http.StatusInternalServerError)
 	}
 	return c.JSON(http.StatusOK, cats)
 }
@@ -74,47 +88,159 @@ func (h *CatHandler) ListCats(c echo.Context) error {
 // @Produce json
 // @Param id path int true "ID cat"
 // @Success 200 {object} model.Cat
+// @Header 200 {string} ETag "Version of the cat"
 // @Failure 404 {object} map[string]string "Cat is not found"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /cats/{id} [get]
 func (h *CatHandler) GetCatByID(c echo.Context) error {
 	id, _ := strconv.Atoi(c.Param("id"))
-	cat, err := h.catUC.GetCat(context.Background(), id)
+	cat, err := h.catUC.GetCat(c.Request().Context(), id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return errorJSON(c, err, http.StatusInternalServerError)
 	}
 	if cat == nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "cat not found"})
 	}
+	setETag(c, cat.Version)
 	return c.JSON(http.StatusOK, cat)
 }
 
 // UpdateSalary Updates a cat's salary.
 // @Summary Update a cat's salary
-// @Description Updates a cat's salary for his ID
+// @Description Records a salary change in the cat's history and applies it. Raises above the configured threshold stay pending until approved. The effective date defaults to today and cannot be in the future.
 // @Tags cats
 // @Accept json
 // @Produce json
 // @Param id path int true "ID кота"
-// @Param salary body float64 true "New salary"
-// @Success 200
+// @Param salary body salaryRequest true "New salary"
+// @Param If-Match header string false "Expected version (ETag) of the cat"
+// @Success 200 {object} model.SalaryChange "Salary change applied"
+// @Success 202 {object} model.SalaryChange "Salary change waiting for approval"
 // @Failure 400 {object} map[string]string “Incorrect request”
+// @Failure 404 {object} map[string]string "Cat is not found"
+// @Failure 409 {object} map[string]string "Conflict (salary change is already waiting for approval)"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 412 {object} map[string]string "Cat was modified (version mismatch)"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /cats/{id}/salary [put]
 func (h *CatHandler) UpdateSalary(c echo.Context) error {
 	id, _ := strconv.Atoi(c.Param("id"))
-	type salaryReq struct {
-		Salary float64 `json:"salary"`
-	}
-	var req salaryReq
+	var req salaryRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	if err := h.catUC.UpdateCatSalary(context.Background(), id, req.Salary); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	change := &model.SalaryChange{
+		NewSalary: model.Money{Amount: req.Salary, Currency: req.Currency},
+		Reason:    req.Reason,
 	}
-	return c.NoContent(http.StatusOK)
+	if req.EffectiveDate != "" {
+		date, err := time.Parse(time.DateOnly, req.EffectiveDate)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid effective_date, expected YYYY-MM-DD"})
+		}
+		change.EffectiveDate = date
+	}
+
+	if err := h.catUC.UpdateCatSalary(c.Request().Context(), id, change); err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	if change.Status == model.SalaryChangePending {
+		return c.JSON(http.StatusAccepted, change)
+	}
+	return c.JSON(http.StatusOK, change)
+}
+
+type salaryRequest struct {
+	Salary        model.Amount `json:"salary" swaggertype:"string" example:"1200.00"`
+	Currency      string       `json:"currency,omitempty" example:"USD"`
+	Reason        string       `json:"reason" example:"Successful mission in Meowland"`
+	EffectiveDate string       `json:"effective_date,omitempty" example:"2023-01-01"`
+}
+
+// GetSalaryHistory Returns the salary history of the cat.
+// @Summary Salary history of a cat
+// @Description Gets all salary changes of the cat (applied, pending and rejected), newest first
+// @Tags cats
+// @Accept json
+// @Produce json
+// @Param id path int true "ID cat"
+// @Success 200 {array} model.SalaryChange
+// @Failure 404 {object} map[string]string "Cat is not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /cats/{id}/salary-history [get]
+func (h *CatHandler) GetSalaryHistory(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	history, err := h.catUC.GetSalaryHistory(c.Request().Context(), id)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, history)
+}
+
+// ApproveSalaryChange Approves a pending salary change.
+// @Summary Approve a salary change
+// @Description Approves a pending salary change and applies the new salary to the cat. The requester of the change cannot approve it.
+// @Tags cats
+// @Accept json
+// @Produce json
+// @Param id path int true "ID cat"
+// @Param changeID path int true "ID salary change"
+// @Success 200 {object} model.SalaryChange
+// @Failure 404 {object} map[string]string "Salary change is not found"
+// @Failure 409 {object} map[string]string "Conflict (salary change is not pending or the salary changed meanwhile)"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden (e.g. approving an own request)"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /cats/{id}/salary-history/{changeID}/approve [put]
+func (h *CatHandler) ApproveSalaryChange(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	changeID, _ := strconv.Atoi(c.Param("changeID"))
+	change, err := h.catUC.ApproveSalaryChange(c.Request().Context(), id, changeID)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, change)
+}
+
+// RejectSalaryChange Rejects a pending salary change.
+// @Summary Reject a salary change
+// @Description Rejects a pending salary change, the cat keeps the current salary
+// @Tags cats
+// @Accept json
+// @Produce json
+// @Param id path int true "ID cat"
+// @Param changeID path int true "ID salary change"
+// @Success 200 {object} model.SalaryChange
+// @Failure 404 {object} map[string]string "Salary change is not found"
+// @Failure 409 {object} map[string]string "Conflict (salary change is not pending)"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /cats/{id}/salary-history/{changeID}/reject [put]
+func (h *CatHandler) RejectSalaryChange(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	changeID, _ := strconv.Atoi(c.Param("changeID"))
+	change, err := h.catUC.RejectSalaryChange(c.Request().Context(), id, changeID)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, change)
 }
 
 // DeleteCat Removes the cat for his ID.
@@ -124,13 +250,19 @@ func (h *CatHandler) UpdateSalary(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID cat"
+// @Param If-Match header string false "Expected version (ETag) of the cat"
 // @Success 200
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 412 {object} map[string]string "Cat was modified (version mismatch)"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /cats/{id} [delete]
 func (h *CatHandler) DeleteCat(c echo.Context) error {
 	id, _ := strconv.Atoi(c.Param("id"))
-	if err := h.catUC.DeleteCat(context.Background(), id); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if err := h.catUC.DeleteCat(c.Request().Context(), id); err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
 	}
 	return c.NoContent(http.StatusOK)
 }
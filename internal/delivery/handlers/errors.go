@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+// errorJSON writes err as {"error": "..."} using the status matching usecase errors,
+// or fallback when err is not one of them.
+func errorJSON(c echo.Context, err error, fallback int) error {
+	status := fallback
+	switch {
+	case errors.Is(err, usecase.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, usecase.ErrConflict), errors.Is(err, domain.ErrRuleViolation):
+		status = http.StatusConflict
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = http.StatusBadRequest
+	case errors.Is(err, usecase.ErrUnauthenticated):
+		status = http.StatusUnauthorized
+	case errors.Is(err, usecase.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, usecase.ErrPreconditionFailed), errors.Is(err, domain.ErrVersionConflict):
+		status = http.StatusPreconditionFailed
+	}
+	return c.JSON(status, map[string]string{"error": err.Error()})
+}
+
+// setETag sets the ETag response header to the given entity version,
+// clients send it back in If-Match to modify the entity.
+func setETag(c echo.Context, version int) {
+	c.Response().Header().Set("ETag", model.ETag(version))
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+func TestErrorJSONStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("cat 1 has version \"1\": %w", usecase.ErrPreconditionFailed), http.StatusPreconditionFailed},
+		{domain.ErrVersionConflict, http.StatusPreconditionFailed},
+		{fmt.Errorf("cat 1: %w", usecase.ErrNotFound), http.StatusNotFound},
+		{usecase.ErrInvalidInput, http.StatusBadRequest},
+		{usecase.ErrForbidden, http.StatusForbidden},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+	e := echo.New()
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/cats/1", nil), rec)
+		if err := errorJSON(c, tt.err, http.StatusInternalServerError); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != tt.want {
+			t.Errorf("%v: status = %d, want %d", tt.err, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+// defaultHeartbeat is used when no heartbeat interval is configured.
+const defaultHeartbeat = 15 * time.Second
+
+type EventStreamHandler struct {
+	streamUC  usecase.EventStreamUsecase
+	heartbeat time.Duration
+}
+
+func NewEventStreamHandler(e *echo.Echo, streamUC usecase.EventStreamUsecase, heartbeat time.Duration) {
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
+	handler := &EventStreamHandler{streamUC: streamUC, heartbeat: heartbeat}
+
+	e.GET("/events/stream", handler.Stream)
+}
+
+// Stream Streams live mission, target and cat events.
+// @Summary Live event stream
+// @Description Server-Sent Events stream of domain events (event name is the event type, id is the event ID).
+// @Description Reconnecting clients send Last-Event-ID to receive the events they missed, as far as the replay buffer reaches.
+// @Description A comment line is sent as heartbeat when there are no events.
+// @Tags events
+// @Produce text/event-stream
+// @Param mission_id query int false "Only events of this mission and its targets"
+// @Param cat_id query int false "Only events of this cat and its missions"
+// @Param Last-Event-ID header int false "ID of the last event received"
+// @Success 200 {object} model.StreamEvent "Stream of events"
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /events/stream [get]
+func (h *EventStreamHandler) Stream(c echo.Context) error {
+	var filter usecase.EventStreamFilter
+	if v := c.QueryParam("mission_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid mission_id"})
+		}
+		filter.MissionID = id
+	}
+	if v := c.QueryParam("cat_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid cat_id"})
+		}
+		filter.CatID = id
+	}
+	var lastEventID int64
+	if v := c.Request().Header.Get("Last-Event-ID"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid Last-Event-ID"})
+		}
+		lastEventID = id
+	}
+
+	replay, events, cancel, err := h.streamUC.Subscribe(c.Request().Context(), filter, lastEventID)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	defer cancel()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	// Disable response buffering of nginx-like proxies.
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	for _, e := range replay {
+		if err := writeEvent(res, e); err != nil {
+			return nil
+		}
+	}
+	res.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				// Dropped for being too slow, the client reconnects with Last-Event-ID.
+				return nil
+			}
+			if err := writeEvent(res, e); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+	}
+}
+
+func writeEvent(res *echo.Response, e model.StreamEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+// exportFlushEvery is the number of exported rows after which the response is flushed to the client.
+const exportFlushEvery = 500
+
+type ExportHandler struct {
+	exportUC usecase.ExportUsecase
+}
+
+func NewExportHandler(e *echo.Echo, exportUC usecase.ExportUsecase) {
+	handler := &ExportHandler{exportUC: exportUC}
+
+	e.GET("/export/cats", handler.ExportCats)
+	e.GET("/export/missions", handler.ExportMissions)
+	e.GET("/export/audit", handler.ExportAuditEvents)
+}
+
+// ExportCats Streams all cats.
+// @Summary Export cats
+// @Description Streams cats created or modified in [since, until) as NDJSON (default) or CSV
+// @Tags export
+// @Produce application/x-ndjson,text/csv
+// @Param format query string false "ndjson (default) or csv"
+// @Param since query string false "Modified at or after (RFC3339)"
+// @Param until query string false "Modified before (RFC3339)"
+// @Success 200 {array} model.Cat
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /export/cats [get]
+func (h *ExportHandler) ExportCats(c echo.Context) error {
+	filter, format, err := exportParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	w := newExportWriter(c, format, "cats",
+		[]string{"id", "name", "years_of_experience", "breed", "salary", "currency", "created_at"})
+	err = h.exportUC.ExportCats(c.Request().Context(), filter, func(cat model.Cat) error {
+		return w.write(cat, []string{
+			strconv.Itoa(cat.ID),
+			cat.Name,
+			strconv.Itoa(cat.YearsOfExperience),
+			cat.Breed,
+			cat.Salary.Amount.String(),
+			cat.Salary.Currency,
+			cat.CreatedAt.Format(time.RFC3339),
+		})
+	})
+	return w.finish(err)
+}
+
+// ExportMissions Streams all missions with their targets.
+// @Summary Export missions
+// @Description Streams missions created or modified in [since, until), including changes of their targets, with nested targets as NDJSON (default), or as CSV with one row per target
+// @Tags export
+// @Produce application/x-ndjson,text/csv
+// @Param format query string false "ndjson (default) or csv"
+// @Param since query string false "Modified at or after (RFC3339)"
+// @Param until query string false "Modified before (RFC3339)"
+// @Success 200 {array} model.Mission
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /export/missions [get]
+func (h *ExportHandler) ExportMissions(c echo.Context) error {
+	filter, format, err := exportParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	w := newExportWriter(c, format, "missions", []string{
+		"mission_id", "cat_id", "completed", "completed_at", "created_at",
+		"target_id", "target_name", "target_country", "target_notes", "target_complete",
+	})
+	err = h.exportUC.ExportMissions(c.Request().Context(), filter, func(m model.Mission) error {
+		if format != exportFormatCSV {
+			return w.write(m, nil)
+		}
+		mission := []string{
+			strconv.Itoa(m.ID),
+			optionalInt(m.CatID),
+			strconv.FormatBool(m.Completed),
+			optionalTime(m.CompletedAt),
+			m.CreatedAt.Format(time.RFC3339),
+		}
+		if len(m.Targets) == 0 {
+			return w.write(nil, append(mission, "", "", "", "", ""))
+		}
+		for _, t := range m.Targets {
+			row := append(mission[:len(mission):len(mission)],
+				strconv.Itoa(t.ID), t.Name, t.Country, t.Notes, strconv.FormatBool(t.Complete))
+			if err := w.write(nil, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return w.finish(err)
+}
+
+// ExportAuditEvents Streams audit events.
+// @Summary Export audit events
+// @Description Streams audit events created in [since, until) as NDJSON (default) or CSV
+// @Tags export
+// @Produce application/x-ndjson,text/csv
+// @Param format query string false "ndjson (default) or csv"
+// @Param since query string false "Created at or after (RFC3339)"
+// @Param until query string false "Created before (RFC3339)"
+// @Success 200 {array} model.AuditEvent
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /export/audit [get]
+func (h *ExportHandler) ExportAuditEvents(c echo.Context) error {
+	filter, format, err := exportParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	w := newExportWriter(c, format, "audit", []string{
+		"id", "actor", "action", "entity_type", "entity_id", "before", "after", "request_id", "created_at",
+	})
+	err = h.exportUC.ExportAuditEvents(c.Request().Context(), filter, func(e model.AuditEvent) error {
+		return w.write(e, []string{
+			strconv.FormatInt(e.ID, 10),
+			e.Actor,
+			e.Action,
+			e.EntityType,
+			strconv.Itoa(e.EntityID),
+			string(e.Before),
+			string(e.After),
+			e.RequestID,
+			e.CreatedAt.Format(time.RFC3339),
+		})
+	})
+	return w.finish(err)
+}
+
+const (
+	exportFormatNDJSON = "ndjson"
+	exportFormatCSV    = "csv"
+)
+
+func exportParams(c echo.Context) (model.ExportFilter, string, error) {
+	var (
+		filter model.ExportFilter
+		err    error
+	)
+	format := c.QueryParam("format")
+	switch format {
+	case "":
+		format = exportFormatNDJSON
+	case exportFormatNDJSON, exportFormatCSV:
+	default:
+		return filter, "", errors.New("invalid format, expected ndjson or csv")
+	}
+	if v := c.QueryParam("since"); v != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, "", errors.New("invalid since, expected RFC3339")
+		}
+	}
+	if v := c.QueryParam("until"); v != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, "", errors.New("invalid until, expected RFC3339")
+		}
+	}
+	return filter, format, nil
+}
+
+// exportWriter writes rows as NDJSON or CSV. The response is committed lazily on the first row,
+// so errors happening before any output can still be reported with a proper status.
+type exportWriter struct {
+	c       echo.Context
+	format  string
+	name    string
+	header  []string
+	csv     *csv.Writer
+	json    *json.Encoder
+	started bool
+	rows    int
+}
+
+func newExportWriter(c echo.Context, format, name string, header []string) *exportWriter {
+	return &exportWriter{c: c, format: format, name: name, header: header}
+}
+
+func (w *exportWriter) start() error {
+	w.started = true
+	res := w.c.Response()
+	if w.format == exportFormatCSV {
+		res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+w.name+`.csv"`)
+		res.WriteHeader(http.StatusOK)
+		w.csv = csv.NewWriter(res)
+		return w.csv.Write(w.header)
+	}
+	res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+	res.WriteHeader(http.StatusOK)
+	w.json = json.NewEncoder(res)
+	return nil
+}
+
+// write outputs v as an NDJSON line, or record as a CSV row.
+func (w *exportWriter) write(v any, record []string) error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+	var err error
+	if w.csv != nil {
+		err = w.csv.Write(record)
+	} else {
+		err = w.json.Encode(v)
+	}
+	if err != nil {
+		return err
+	}
+	w.rows++
+	if w.rows%exportFlushEvery == 0 {
+		w.flush()
+	}
+	return nil
+}
+
+func (w *exportWriter) flush() {
+	if w.csv != nil {
+		w.csv.Flush()
+	}
+	w.c.Response().Flush()
+}
+
+// finish completes the response. Once rows were sent the status cannot change anymore,
+// so a late error only aborts the stream and is left for the server to log.
+func (w *exportWriter) finish(err error) error {
+	if err != nil {
+		if !w.started {
+			return errorJSON(w.c, err, http.StatusInternalServerError)
+		}
+		w.flush()
+		return err
+	}
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+	w.flush()
+	if w.csv != nil {
+		return w.csv.Error()
+	}
+	return nil
+}
+
+func optionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func optionalTime(v *time.Time) string {
+	if v == nil {
+		return ""
+	}
+	return v.Format(time.RFC3339)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+// HealthPaths are the routes of the health handler, they are served without credentials.
+var HealthPaths = []string{"/healthz", "/readyz", "/version"}
+
+type HealthHandler struct {
+	healthUC usecase.HealthUsecase
+}
+
+func NewHealthHandler(e *echo.Echo, healthUC usecase.HealthUsecase) {
+	handler := &HealthHandler{healthUC: healthUC}
+
+	e.GET("/healthz", handler.Liveness)
+	e.GET("/readyz", handler.Readiness)
+	e.GET("/version", handler.Version)
+}
+
+// Liveness Reports that the process is alive.
+// @Summary Liveness probe
+// @Description Responds as long as the process serves HTTP, without checking dependencies
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func (h *HealthHandler) Liveness(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": model.HealthStatusOK})
+}
+
+// Readiness Reports whether the application can serve requests.
+// @Summary Readiness probe
+// @Description Checks Postgres, the schema migrations and optionally thecatapi. Fails with 503 when a check fails or the application is shutting down.
+// @Tags health
+// @Produce json
+// @Success 200 {object} model.Readiness
+// @Failure 503 {object} model.Readiness
+// @Router /readyz [get]
+func (h *HealthHandler) Readiness(c echo.Context) error {
+	readiness := h.healthUC.Readiness(c.Request().Context())
+	status := http.StatusOK
+	if readiness.Status != model.HealthStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, readiness)
+}
+
+// Version Returns the build of the application.
+// @Summary Build information
+// @Description Gets the name, version, git commit and build time of the running build
+// @Tags health
+// @Produce json
+// @Success 200 {object} model.BuildInfo
+// @Router /version [get]
+func (h *HealthHandler) Version(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.healthUC.BuildInfo())
+}
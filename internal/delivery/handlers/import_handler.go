@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+const (
+	mimeCSV    = "text/csv"
+	mimeNDJSON = "application/x-ndjson"
+
+	// maxImportLineSize limits a single NDJSON line.
+	maxImportLineSize = 1 << 20
+)
+
+type ImportHandler struct {
+	importUC usecase.ImportUsecase
+}
+
+func NewImportHandler(e *echo.Echo, importUC usecase.ImportUsecase) {
+	handler := &ImportHandler{importUC: importUC}
+
+	e.POST("/import/cats", handler.ImportCats)
+	e.POST("/import/missions", handler.ImportMissions)
+}
+
+// ImportCats Creates cats in bulk.
+// @Summary Import cats
+// @Description Creates cats from a CSV (header: name,years_of_experience,breed,salary,currency) or NDJSON (one model.Cat per line) upload.
+// @Description In atomic mode (default) either all rows are created or none; in best_effort mode every valid row is created on its own.
+// @Tags import
+// @Accept text/csv,application/x-ndjson
+// @Produce json
+// @Param mode query string false "atomic (default) or best_effort"
+// @Param file body string true "CSV or NDJSON content"
+// @Success 200 {object} model.ImportReport "Every row was imported"
+// @Success 207 {object} model.ImportReport "Some rows failed"
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 415 {object} map[string]string "Unsupported content type"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /import/cats [post]
+func (h *ImportHandler) ImportCats(c echo.Context) error {
+	format, err := importFormat(c)
+	if err != nil {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": err.Error()})
+	}
+
+	var rows []usecase.CatImportRow
+	if format == mimeCSV {
+		rows, err = decodeCatsCSV(c.Request().Body)
+	} else {
+		rows, err = decodeNDJSON(c.Request().Body, func(row int, cat model.Cat, err error) usecase.CatImportRow {
+			return usecase.CatImportRow{Row: row, Cat: cat, Err: err}
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	report, err := h.importUC.ImportCats(c.Request().Context(), importMode(c), rows)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(importStatus(report), report)
+}
+
+// ImportMissions Creates missions in bulk.
+// @Summary Import missions
+// @Description Creates missions from a CSV or NDJSON (one model.Mission with targets per line) upload.
+// @Description CSV header: mission_ref,cat_id,target_name,target_country,target_notes; rows sharing mission_ref become targets of one mission.
+// @Description In atomic mode (default) either all missions are created or none; in best_effort mode every valid mission is created on its own.
+// @Tags import
+// @Accept text/csv,application/x-ndjson
+// @Produce json
+// @Param mode query string false "atomic (default) or best_effort"
+// @Param file body string true "CSV or NDJSON content"
+// @Success 200 {object} model.ImportReport "Every mission was imported"
+// @Success 207 {object} model.ImportReport "Some missions failed"
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 415 {object} map[string]string "Unsupported content type"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /import/missions [post]
+func (h *ImportHandler) ImportMissions(c echo.Context) error {
+	format, err := importFormat(c)
+	if err != nil {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": err.Error()})
+	}
+
+	var rows []usecase.MissionImportRow
+	if format == mimeCSV {
+		rows, err = decodeMissionsCSV(c.Request().Body)
+	} else {
+		rows, err = decodeNDJSON(c.Request().Body, func(row int, m model.Mission, err error) usecase.MissionImportRow {
+			return usecase.MissionImportRow{Row: row, Mission: m, Err: err}
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	report, err := h.importUC.ImportMissions(c.Request().Context(), importMode(c), rows)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(importStatus(report), report)
+}
+
+func importFormat(c echo.Context) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if err == nil && (mediaType == mimeCSV || mediaType == mimeNDJSON) {
+		return mediaType, nil
+	}
+	return "", fmt.Errorf("content type must be %s or %s", mimeCSV, mimeNDJSON)
+}
+
+func importMode(c echo.Context) string {
+	if mode := c.QueryParam("mode"); mode != "" {
+		return mode
+	}
+	return model.ImportModeAtomic
+}
+
+func importStatus(report *model.ImportReport) int {
+	if report.Failed > 0 {
+		return http.StatusMultiStatus
+	}
+	return http.StatusOK
+}
+
+// decodeNDJSON decodes one JSON object per line, skipping blank lines. Rows are numbered by line.
+func decodeNDJSON[T, R any](r io.Reader, newRow func(row int, v T, err error) R) ([]R, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+
+	var rows []R
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if len(rows) >= usecase.MaxImportRows {
+			return nil, fmt.Errorf("too many rows, at most %d are allowed", usecase.MaxImportRows)
+		}
+		var v T
+		err := json.Unmarshal([]byte(text), &v)
+		rows = append(rows, newRow(line, v, err))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// csvRecords reads a CSV with a header, returning the column index by name and the records.
+func csvRecords(r io.Reader, required ...string) (map[string]int, [][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, errors.New("empty CSV")
+	} else if err != nil {
+		return nil, nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("CSV header must contain %q", name)
+		}
+	}
+
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+		if len(records) >= usecase.MaxImportRows {
+			return nil, nil, fmt.Errorf("too many rows, at most %d are allowed", usecase.MaxImportRows)
+		}
+		records = append(records, record)
+	}
+	return columns, records, nil
+}
+
+func csvField(record []string, columns map[string]int, name string) string {
+	i, ok := columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func decodeCatsCSV(r io.Reader) ([]usecase.CatImportRow, error) {
+	columns, records, err := csvRecords(r, "name", "years_of_experience", "breed", "salary")
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]usecase.CatImportRow, 0, len(records))
+	for i, record := range records {
+		// Row 1 is the header.
+		row := usecase.CatImportRow{Row: i + 2}
+		row.Cat.Name = csvField(record, columns, "name")
+		row.Cat.Breed = csvField(record, columns, "breed")
+		row.Cat.Salary.Currency = csvField(record, columns, "currency")
+		if row.Cat.YearsOfExperience, err = strconv.Atoi(csvField(record, columns, "years_of_experience")); err != nil {
+			row.Err = errors.New("invalid years_of_experience")
+		} else if row.Cat.Salary.Amount, err = model.ParseAmount(csvField(record, columns, "salary")); err != nil {
+			row.Err = err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func decodeMissionsCSV(r io.Reader) ([]usecase.MissionImportRow, error) {
+	columns, records, err := csvRecords(r, "mission_ref", "target_name", "target_country")
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []usecase.MissionImportRow
+	byRef := make(map[string]int)
+	for i, record := range records {
+		line := i + 2
+		ref := csvField(record, columns, "mission_ref")
+		idx, ok := byRef[ref]
+		if !ok || ref == "" {
+			idx = len(rows)
+			byRef[ref] = idx
+			rows = append(rows, usecase.MissionImportRow{Row: line})
+		}
+		mr := &rows[idx]
+
+		if v := csvField(record, columns, "cat_id"); v != "" {
+			catID, err := strconv.Atoi(v)
+			if err != nil {
+				mr.Err = fmt.Errorf("line %d: invalid cat_id", line)
+			} else if mr.Mission.CatID != nil && *mr.Mission.CatID != catID {
+				mr.Err = fmt.Errorf("line %d: mission %q has different cat_id values", line, ref)
+			} else {
+				mr.Mission.CatID = &catID
+			}
+		}
+		mr.Mission.Targets = append(mr.Mission.Targets, model.Target{
+			Name:    csvField(record, columns, "target_name"),
+			Country: csvField(record, columns, "target_country"),
+			Notes:   csvField(record, columns, "target_notes"),
+		})
+	}
+	return rows, nil
+}
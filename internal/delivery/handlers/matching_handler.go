@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+type MatchingHandler struct {
+	matchingUC usecase.MatchingUsecase
+}
+
+func NewMatchingHandler(e *echo.Echo, matchingUC usecase.MatchingUsecase) {
+	handler := &MatchingHandler{matchingUC: matchingUC}
+
+	e.GET("/missions/:id/candidates", handler.Candidates)
+	e.POST("/missions/:id/auto-assign", handler.AutoAssign)
+}
+
+// Candidates Returns available cats ranked for the mission.
+// @Summary Candidates for a mission
+// @Description Ranks cats without an active mission by experience, completed targets in the mission countries, breed traits and cost
+// @Tags missions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID mission"
+// @Success 200 {array} model.Candidate
+// @Failure 404 {object} map[string]string "Mission not found"
+// @Failure 409 {object} map[string]string "Conflict (mission is completed or already assigned)"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /missions/{id}/candidates [get]
+func (h *MatchingHandler) Candidates(c echo.Context) error {
+	missionID, _ := strconv.Atoi(c.Param("id"))
+	candidates, err := h.matchingUC.Candidates(c.Request().Context(), missionID)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, candidates)
+}
+
+// AutoAssign Assigns the best candidate to the mission.
+// @Summary Auto-assign a cat to a mission
+// @Description Assigns the highest ranked available cat to the mission
+// @Tags missions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID mission"
+// @Success 200 {object} model.Candidate
+// @Failure 404 {object} map[string]string "Mission not found"
+// @Failure 409 {object} map[string]string "Conflict (mission is completed, already assigned or no cats are available)"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /missions/{id}/auto-assign [post]
+func (h *MatchingHandler) AutoAssign(c echo.Context) error {
+	missionID, _ := strconv.Atoi(c.Param("id"))
+	candidate, err := h.matchingUC.AutoAssign(c.Request().Context(), missionID)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, candidate)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+type MeHandler struct {
+	agentUC usecase.AgentUsecase
+}
+
+func NewMeHandler(e *echo.Echo, agentUC usecase.AgentUsecase) {
+	handler := &MeHandler{agentUC: agentUC}
+
+	e.GET("/me", handler.GetMe)
+	e.GET("/me/mission", handler.GetMyMission)
+	e.PUT("/me/targets/:id/notes", handler.UpdateMyTargetNotes)
+	e.PUT("/me/targets/:id/complete", handler.CompleteMyTarget)
+}
+
+// GetMe Returns the calling cat.
+// @Summary Who am I
+// @Description Gets the identity of the caller and the spy cat it acts as
+// @Tags me
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.AgentProfile
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "The caller does not act as a cat"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /me [get]
+func (h *MeHandler) GetMe(c echo.Context) error {
+	profile, err := h.agentUC.Me(c.Request().Context())
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, profile)
+}
+
+// GetMyMission Returns the active mission of the calling cat.
+// @Summary My active mission
+// @Description Gets the mission the calling cat is working on, with its targets
+// @Tags me
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.Mission
+// @Header 200 {string} ETag "Version of the mission"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "The caller does not act as a cat"
+// @Failure 404 {object} map[string]string "The cat has no active mission"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /me/mission [get]
+func (h *MeHandler) GetMyMission(c echo.Context) error {
+	mission, err := h.agentUC.ActiveMission(c.Request().Context())
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	setETag(c, mission.Version)
+	return c.JSON(http.StatusOK, mission)
+}
+
+// UpdateMyTargetNotes Updates notes of a target of the active mission.
+// @Summary Update notes of my target
+// @Description Updates notes of a target of the calling cat's active mission
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param id path int true "ID target"
+// @Param notes body string true "New notes"
+// @Param If-Match header string false "Expected version (ETag) of the target"
+// @Success 200
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "The caller does not act as a cat"
+// @Failure 404 {object} map[string]string "Target is not on the active mission"
+// @Failure 409 {object} map[string]string "Conflict (target completed)"
+// @Failure 412 {object} map[string]string "Target was modified (version mismatch)"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /me/targets/{id}/notes [put]
+func (h *MeHandler) UpdateMyTargetNotes(c echo.Context) error {
+	targetID, _ := strconv.Atoi(c.Param("id"))
+	var req struct {
+		Notes string `json:"notes"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := h.agentUC.UpdateTargetNotes(c.Request().Context(), targetID, req.Notes); err != nil {
+		return errorJSON(c, err, http.StatusConflict)
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// CompleteMyTarget Completes a target of the active mission.
+// @Summary Complete my target
+// @Description Marks a target of the calling cat's active mission as completed
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param id path int true "ID target"
+// @Param If-Match header string false "Expected version (ETag) of the target"
+// @Success 200
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "The caller does not act as a cat"
+// @Failure 404 {object} map[string]string "Target is not on the active mission"
+// @Failure 409 {object} map[string]string "Conflict (target already completed)"
+// @Failure 412 {object} map[string]string "Target was modified (version mismatch)"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /me/targets/{id}/complete [put]
+func (h *MeHandler) CompleteMyTarget(c echo.Context) error {
+	targetID, _ := strconv.Atoi(c.Param("id"))
+	if err := h.agentUC.CompleteTarget(c.Request().Context(), targetID); err != nil {
+		return errorJSON(c, err, http.StatusConflict)
+	}
+	return c.NoContent(http.StatusOK)
+}
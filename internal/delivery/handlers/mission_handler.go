@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"net/http"
 	"strconv"
 
@@ -25,11 +24,16 @@ func NewMissionHandler(e *echo.Echo, mu usecase.MissionUsecase) {
 	e.DELETE("/missions/:id", handler.DeleteMission)
 
 	e.POST("/missions/:id/assign/:catID", handler.AssignCat)
+	e.DELETE("/missions/:id/assign", handler.UnassignCat)
+	e.GET("/missions/:id/assignments", handler.ListMissionAssignments)
+	e.GET("/cats/:id/missions", handler.ListCatAssignments)
 
 	e.POST("/missions/:id/targets", handler.AddTarget)
+	e.GET("/targets/:targetID", handler.GetTarget)
 	e.DELETE("/targets/:targetID", handler.DeleteTarget)
 	e.PUT("/targets/:targetID/complete", handler.CompleteTarget)
 	e.PUT("/targets/:targetID/notes", handler.UpdateTargetNotes)
+	e.POST("/targets/batch", handler.ExecuteTargetBatch)
 }
 
 // CreateMission Creates a new mission.
@@ -39,17 +43,24 @@ func NewMissionHandler(e *echo.Echo, mu usecase.MissionUsecase) {
 // @Accept json
 // @Produce json
 // @Param mission body model.Mission true "Mission data"
+// @Param Idempotency-Key header string false "Key to safely retry the request, the first response is replayed"
 // @Success 201 {object} model.Mission
 // @Failure 400 {object} map[string]string “Incorrect request”
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 422 {object} map[string]string "Idempotency key was used for a different request"
+// @Failure 413 {object} map[string]string "Body of a request with an idempotency key is too large"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /missions [post]
 func (h *MissionHandler) CreateMission(c echo.Context) error {
 	var mission model.Mission
 	if err := c.Bind(&mission); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	if err := h.missionUC.CreateMission(context.Background(), &mission); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if err := h.missionUC.CreateMission(c.Request().Context(), &mission); err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
 	}
 	return c.JSON(http.StatusCreated, mission)
 }
@@ -62,11 +73,15 @@ func (h *MissionHandler) CreateMission(c echo.Context) error {
 // @Produce json
 // @Success 200 {array} model.Mission
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /missions [get]
 func (h *MissionHandler) ListMissions(c echo.Context) error {
-	missions, err := h.missionUC.ListMissions(context.Background())
+	missions, err := h.missionUC.ListMissions(c.Request().Context())
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return errorJSON(c, err, http.StatusInternalServerError)
 	}
 	return c.JSON(http.StatusOK, missions)
 }
@@ -79,18 +94,24 @@ func (h *MissionHandler) ListMissions(c echo.Context) error {
 // @Produce json
 // @Param id path int true "ID mission"
 // @Success 200 {object} model.Mission
+// @Header 200 {string} ETag "Version of the mission, targets carry their own versions"
 // @Failure 404 {object} map[string]string "Mission not found"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /missions/{id} [get]
 func (h *MissionHandler) GetMission(c echo.Context) error {
 	id, _ := strconv.Atoi(c.Param("id"))
-	mission, err := h.missionUC.GetMission(context.Background(), id)
+	mission, err := h.missionUC.GetMission(c.Request().Context(), id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return errorJSON(c, err, http.StatusInternalServerError)
 	}
 	if mission == nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "mission not found"})
 	}
+	setETag(c, mission.Version)
 	return c.JSON(http.StatusOK, mission)
 }
 
@@ -101,13 +122,19 @@ func (h *MissionHandler) GetMission(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID місії"
+// @Param If-Match header string false "Expected version (ETag) of the mission"
 // @Success 200
 // @Failure 409 {object} map[string]string “Conflict (mission is already completed)”
+// @Failure 412 {object} map[string]string "Mission was modified (version mismatch)"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /missions/{id}/complete [put]
 func (h *MissionHandler) CompleteMission(c echo.Context) error {
 	id, _ := strconv.Atoi(c.Param("id"))
-	if err := h.missionUC.CompleteMission(context.Background(), id); err != nil {
-		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	if err := h.missionUC.CompleteMission(c.Request().Context(), id); err != nil {
+		return errorJSON(c, err, http.StatusConflict)
 	}
 	return c.NoContent(http.StatusOK)
 }
@@ -119,13 +146,19 @@ func (h *MissionHandler) CompleteMission(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID mission"
+// @Param If-Match header string false "Expected version (ETag) of the mission"
 // @Success 200
 // @Failure 409 {object} map[string]string "Conflict ( mission assigned a cat)"
+// @Failure 412 {object} map[string]string "Mission was modified (version mismatch)"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /missions/{id} [delete]
 func (h *MissionHandler) DeleteMission(c echo.Context) error {
 	id, _ := strconv.Atoi(c.Param("id"))
-	if err := h.missionUC.DeleteMission(context.Background(), id); err != nil {
-		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	if err := h.missionUC.DeleteMission(c.Request().Context(), id); err != nil {
+		return errorJSON(c, err, http.StatusConflict)
 	}
 	return c.NoContent(http.StatusOK)
 }
@@ -138,18 +171,96 @@ func (h *MissionHandler) DeleteMission(c echo.Context) error {
 // @Produce json
 // @Param id path int true "ID mission"
 // @Param catID path int true "ID cat"
+// @Param If-Match header string false "Expected version (ETag) of the mission"
 // @Success 200
 // @Failure 409 {object} map[string]string "Conflict ( cat already has an active mission)"
+// @Failure 412 {object} map[string]string "Mission was modified (version mismatch)"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /missions/{id}/assign/{catID} [post]
 func (h *MissionHandler) AssignCat(c echo.Context) error {
 	missionID, _ := strconv.Atoi(c.Param("id"))
 	catID, _ := strconv.Atoi(c.Param("catID"))
-	if err := h.missionUC.AssignCatToMission(context.Background(), missionID, catID); err != nil {
-		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	if err := h.missionUC.AssignCatToMission(c.Request().Context(), missionID, catID); err != nil {
+		return errorJSON(c, err, http.StatusConflict)
 	}
 	return c.NoContent(http.StatusOK)
 }
 
+// UnassignCat removes the cat from a mission.
+// @Summary Unassign the cat from a mission
+// @Description Removes the cat from an active mission, the assignment is kept in the mission history
+// @Tags missions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID mission"
+// @Param If-Match header string false "Expected version (ETag) of the mission"
+// @Success 200
+// @Failure 404 {object} map[string]string "Mission not found"
+// @Failure 409 {object} map[string]string "Conflict (mission is completed or has no cat)"
+// @Failure 412 {object} map[string]string "Mission was modified (version mismatch)"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /missions/{id}/assign [delete]
+func (h *MissionHandler) UnassignCat(c echo.Context) error {
+	missionID, _ := strconv.Atoi(c.Param("id"))
+	if err := h.missionUC.UnassignCat(c.Request().Context(), missionID); err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// ListMissionAssignments returns the assignment history of the mission.
+// @Summary Assignment history of a mission
+// @Description Gets all cats ever assigned to the mission, newest first
+// @Tags missions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID mission"
+// @Success 200 {array} model.MissionAssignment
+// @Failure 404 {object} map[string]string "Mission not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /missions/{id}/assignments [get]
+func (h *MissionHandler) ListMissionAssignments(c echo.Context) error {
+	missionID, _ := strconv.Atoi(c.Param("id"))
+	assignments, err := h.missionUC.ListMissionAssignments(c.Request().Context(), missionID)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, assignments)
+}
+
+// ListCatAssignments returns past and present missions of the cat.
+// @Summary Missions of a cat
+// @Description Gets all assignments of the cat to missions, past and present, newest first
+// @Tags missions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID cat"
+// @Success 200 {array} model.MissionAssignment
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /cats/{id}/missions [get]
+func (h *MissionHandler) ListCatAssignments(c echo.Context) error {
+	catID, _ := strconv.Atoi(c.Param("id"))
+	assignments, err := h.missionUC.ListCatAssignments(c.Request().Context(), catID)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, assignments)
+}
+
 // AddTarget adds a new target to the mission.
 // @Summary Add the target to the mission
 // @Description Adds a new target to a particular mission
@@ -158,9 +269,16 @@ func (h *MissionHandler) AssignCat(c echo.Context) error {
 // @Produce json
 // @Param id path int true "ID mission"
 // @Param target body model.Target true "Дані цілі"
+// @Param Idempotency-Key header string false "Key to safely retry the request, the first response is replayed"
 // @Success 201 {object} model.Target
 // @Failure 400 {object} map[string]string “Incorrect request”
 // @Failure 409 {object} map[string]string "Conflict (mission is completed or has a maximum of purposes)"
+// @Failure 422 {object} map[string]string "Idempotency key was used for a different request"
+// @Failure 413 {object} map[string]string "Body of a request with an idempotency key is too large"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /missions/{id}/targets [post]
 func (h *MissionHandler) AddTarget(c echo.Context) error {
 	missionID, _ := strconv.Atoi(c.Param("id"))
@@ -169,12 +287,41 @@ func (h *MissionHandler) AddTarget(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 	target.MissionID = missionID
-	if err := h.missionUC.AddTarget(context.Background(), &target); err != nil {
-		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	if err := h.missionUC.AddTarget(c.Request().Context(), &target); err != nil {
+		return errorJSON(c, err, http.StatusConflict)
 	}
 	return c.JSON(http.StatusCreated, target)
 }
 
+// GetTarget returns the target by its ID.
+// @Summary Get a target for ID
+// @Description Receives target details by its unique identifier
+// @Tags targets
+// @Accept json
+// @Produce json
+// @Param targetID path int true "ID targets"
+// @Success 200 {object} model.Target
+// @Header 200 {string} ETag "Version of the target"
+// @Failure 404 {object} map[string]string "Target not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /targets/{targetID} [get]
+func (h *MissionHandler) GetTarget(c echo.Context) error {
+	targetID, _ := strconv.Atoi(c.Param("targetID"))
+	target, err := h.missionUC.GetTarget(c.Request().Context(), targetID)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	if target == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "target not found"})
+	}
+	setETag(c, target.Version)
+	return c.JSON(http.StatusOK, target)
+}
+
 // DeleteTarget Removes the target.
 // @Summary Remove the target
 // @Description Removes the target for her ID
@@ -182,13 +329,19 @@ func (h *MissionHandler) AddTarget(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param targetID path int true "ID targets"
+// @Param If-Match header string false "Expected version (ETag) of the target"
 // @Success 200
 // @Failure 409 {object} map[string]string "Conflict (eg target completed or mission has only one target)"
+// @Failure 412 {object} map[string]string "Target was modified (version mismatch)"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /targets/{targetID} [delete]
 func (h *MissionHandler) DeleteTarget(c echo.Context) error {
 	targetID, _ := strconv.Atoi(c.Param("targetID"))
-	if err := h.missionUC.DeleteTarget(context.Background(), targetID); err != nil {
-		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	if err := h.missionUC.DeleteTarget(c.Request().Context(), targetID); err != nil {
+		return errorJSON(c, err, http.StatusConflict)
 	}
 	return c.NoContent(http.StatusOK)
 }
@@ -200,13 +353,19 @@ func (h *MissionHandler) DeleteTarget(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param targetID path int true "ID targets"
+// @Param If-Match header string false "Expected version (ETag) of the target"
 // @Success 200
 // @Failure 409 {object} map[string]string “Conflict (eg target already completed)”
+// @Failure 412 {object} map[string]string "Target was modified (version mismatch)"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /targets/{targetID}/complete [put]
 func (h *MissionHandler) CompleteTarget(c echo.Context) error {
 	targetID, _ := strconv.Atoi(c.Param("targetID"))
-	if err := h.missionUC.CompleteTarget(context.Background(), targetID); err != nil {
-		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	if err := h.missionUC.CompleteTarget(c.Request().Context(), targetID); err != nil {
+		return errorJSON(c, err, http.StatusConflict)
 	}
 	return c.NoContent(http.StatusOK)
 }
@@ -219,9 +378,15 @@ func (h *MissionHandler) CompleteTarget(c echo.Context) error {
 // @Produce json
 // @Param targetID path int true "ID targets"
 // @Param notes body string true "New notes"
+// @Param If-Match header string false "Expected version (ETag) of the target"
 // @Success 200
 // @Failure 400 {object} map[string]string “Incorrect request”
 // @Failure 409 {object} map[string]string “Conflict (target or mission completed)”
+// @Failure 412 {object} map[string]string "Target was modified (version mismatch)"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /targets/{targetID}/notes [put]
 func (h *MissionHandler) UpdateTargetNotes(c echo.Context) error {
 	targetID, _ := strconv.Atoi(c.Param("targetID"))
@@ -232,8 +397,44 @@ func (h *MissionHandler) UpdateTargetNotes(c echo.Context) error {
 	if err := c.Bind(&nr); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	if err := h.missionUC.UpdateTargetNotes(context.Background(), targetID, nr.Notes); err != nil {
-		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	if err := h.missionUC.UpdateTargetNotes(c.Request().Context(), targetID, nr.Notes); err != nil {
+		return errorJSON(c, err, http.StatusConflict)
 	}
 	return c.NoContent(http.StatusOK)
 }
+
+type targetBatchRequest struct {
+	Operations []model.TargetOperation `json:"operations"`
+}
+
+// ExecuteTargetBatch runs several target operations atomically.
+// @Summary Batch target operations
+// @Description Runs add, complete, update_notes and delete operations in one transaction. Either all of them are applied or none;
+// @Description every operation is reported, including the ones rejected by database triggers.
+// @Tags targets
+// @Accept json
+// @Produce json
+// @Param batch body targetBatchRequest true "Operations"
+// @Success 200 {object} model.TargetBatchResult "All operations were applied"
+// @Failure 400 {object} map[string]string “Incorrect request”
+// @Failure 409 {object} model.TargetBatchResult "An operation failed, nothing was applied"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /targets/batch [post]
+func (h *MissionHandler) ExecuteTargetBatch(c echo.Context) error {
+	var req targetBatchRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	result, err := h.missionUC.ExecuteTargetBatch(c.Request().Context(), req.Operations)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	if !result.Committed {
+		return c.JSON(http.StatusConflict, result)
+	}
+	return c.JSON(http.StatusOK, result)
+}
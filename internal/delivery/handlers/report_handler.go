@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+type ReportHandler struct {
+	reportUC usecase.ReportUsecase
+}
+
+func NewReportHandler(e *echo.Echo, reportUC usecase.ReportUsecase) {
+	handler := &ReportHandler{reportUC: reportUC}
+
+	e.GET("/reports/payroll", handler.Payroll)
+}
+
+// Payroll Returns the agency payroll report.
+// @Summary Payroll report
+// @Description Computes headcount, total, average and median salaries grouped by breed and/or experience band (0-1, 2-4, 5-9, 10+), per currency
+// @Tags reports
+// @Accept json
+// @Produce json,text/csv
+// @Param group_by query string false "Comma separated groupings: breed, experience"
+// @Param as_of query string false "Use salaries effective on this date (YYYY-MM-DD) from the salary history"
+// @Param format query string false "Output format: json (default) or csv"
+// @Success 200 {object} model.PayrollReport
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /reports/payroll [get]
+func (h *ReportHandler) Payroll(c echo.Context) error {
+	var filter model.PayrollFilter
+	if v := c.QueryParam("group_by"); v != "" {
+		filter.GroupBy = strings.Split(v, ",")
+	}
+	if v := c.QueryParam("as_of"); v != "" {
+		asOf, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid as_of, expected YYYY-MM-DD"})
+		}
+		filter.AsOf = &asOf
+	}
+
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "csv" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid format, expected json or csv"})
+	}
+
+	report, err := h.reportUC.PayrollReport(c.Request().Context(), filter)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+
+	if format == "csv" {
+		return writePayrollCSV(c, report)
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+func writePayrollCSV(c echo.Context, report *model.PayrollReport) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="payroll.csv"`)
+	res.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(res)
+	_ = w.Write([]string{"breed", "experience_band", "currency", "headcount", "total", "average", "median"})
+	sections := [][]model.PayrollGroup{report.Groups}
+	if len(report.GroupBy) > 0 {
+		// Without grouping the groups already are the totals.
+		sections = append(sections, report.Totals)
+	}
+	for _, rows := range sections {
+		for _, g := range rows {
+			_ = w.Write([]string{
+				g.Breed,
+				g.ExperienceBand,
+				g.Currency,
+				strconv.Itoa(g.Headcount),
+				g.Total.String(),
+				g.Average.String(),
+				g.Median.String(),
+			})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
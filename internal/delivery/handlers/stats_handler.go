@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+type StatsHandler struct {
+	statsUC usecase.StatsUsecase
+}
+
+func NewStatsHandler(e *echo.Echo, statsUC usecase.StatsUsecase) {
+	handler := &StatsHandler{statsUC: statsUC}
+
+	e.GET("/cats/:id/stats", handler.GetCatStats)
+	e.GET("/cats/leaderboard", handler.Leaderboard)
+}
+
+// GetCatStats Returns performance statistics of the cat.
+// @Summary Cat performance statistics
+// @Description Gets completed missions, completed targets, average time to complete a mission, active mission and countries operated in
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Param id path int true "ID cat"
+// @Success 200 {object} model.CatStats
+// @Failure 404 {object} map[string]string "Cat is not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /cats/{id}/stats [get]
+func (h *StatsHandler) GetCatStats(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	stats, err := h.statsUC.GetCatStats(c.Request().Context(), id)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, stats)
+}
+
+// Leaderboard Returns cats ranked by performance.
+// @Summary Cats leaderboard
+// @Description Ranks cats by one of the performance metrics, best first
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Param sort_by query string false "Metric: completed_missions (default), targets_completed, avg_time_to_complete, countries"
+// @Param limit query int false "Number of cats (default 10, max 100)"
+// @Success 200 {array} model.LeaderboardEntry
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /cats/leaderboard [get]
+func (h *StatsHandler) Leaderboard(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	entries, err := h.statsUC.Leaderboard(c.Request().Context(), c.QueryParam("sort_by"), limit)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, entries)
+}
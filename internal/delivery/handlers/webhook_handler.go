@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+type WebhookHandler struct {
+	webhookUC usecase.WebhookUsecase
+}
+
+func NewWebhookHandler(e *echo.Echo, webhookUC usecase.WebhookUsecase) {
+	handler := &WebhookHandler{webhookUC: webhookUC}
+
+	e.POST("/webhooks", handler.CreateSubscription)
+	e.GET("/webhooks", handler.ListSubscriptions)
+	e.GET("/webhooks/:id", handler.GetSubscription)
+	e.PUT("/webhooks/:id", handler.UpdateSubscription)
+	e.DELETE("/webhooks/:id", handler.DeleteSubscription)
+	e.GET("/webhooks/:id/deliveries", handler.ListDeliveries)
+}
+
+type webhookRequest struct {
+	URL        string   `json:"url" example:"https://ops.example.com/hooks/missions"`
+	Secret     string   `json:"secret,omitempty" example:"my-shared-secret"`
+	EventTypes []string `json:"event_types" example:"MissionCompleted,TargetCompleted"`
+	// Active is only used on update, new subscriptions are always active.
+	Active *bool `json:"active,omitempty" example:"true"`
+}
+
+// CreateSubscription Creates a webhook subscription.
+// @Summary Create a webhook subscription
+// @Description Subscribes an HTTP endpoint to the domain events of the agency. Payloads are signed with HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>"
+// @Description using the secret, sent as X-Webhook-Signature: sha256=<hex>. The secret is generated when omitted and only returned here.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param subscription body webhookRequest true "Subscription"
+// @Success 201 {object} model.WebhookSubscription
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateSubscription(c echo.Context) error {
+	var req webhookRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	sub := &model.WebhookSubscription{URL: req.URL, Secret: req.Secret, EventTypes: req.EventTypes}
+	if err := h.webhookUC.CreateSubscription(c.Request().Context(), sub); err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusCreated, sub)
+}
+
+// ListSubscriptions Returns webhook subscriptions.
+// @Summary List of webhook subscriptions
+// @Description Gets the webhook subscriptions of the agency, without their secrets
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.WebhookSubscription
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListSubscriptions(c echo.Context) error {
+	subs, err := h.webhookUC.ListSubscriptions(c.Request().Context())
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, subs)
+}
+
+// GetSubscription Returns a webhook subscription.
+// @Summary Get a webhook subscription
+// @Description Gets the webhook subscription by its ID, without its secret
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID subscription"
+// @Success 200 {object} model.WebhookSubscription
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetSubscription(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	sub, err := h.webhookUC.GetSubscription(c.Request().Context(), id)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, sub)
+}
+
+// UpdateSubscription Updates a webhook subscription.
+// @Summary Update a webhook subscription
+// @Description Changes the URL, the event types and the active flag. Reactivating a disabled subscription resets its failure counter.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID subscription"
+// @Param subscription body webhookRequest true "Subscription"
+// @Success 200 {object} model.WebhookSubscription
+// @Failure 400 {object} map[string]string "Incorrect request"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateSubscription(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req webhookRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	sub := &model.WebhookSubscription{ID: id, URL: req.URL, EventTypes: req.EventTypes, Active: true}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	if err := h.webhookUC.UpdateSubscription(c.Request().Context(), sub); err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, sub)
+}
+
+// DeleteSubscription Removes a webhook subscription.
+// @Summary Remove a webhook subscription
+// @Description Removes the subscription together with its delivery log
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID subscription"
+// @Success 200
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteSubscription(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	if err := h.webhookUC.DeleteSubscription(c.Request().Context(), id); err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// ListDeliveries Returns the delivery log of a webhook subscription.
+// @Summary Webhook delivery log
+// @Description Gets deliveries of the subscription with the outcome of their last attempt, newest first
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID subscription"
+// @Param limit query int false "Maximum number of deliveries (default 100)"
+// @Success 200 {array} model.WebhookDelivery
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 401 {object} map[string]string "Unauthenticated"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	deliveries, err := h.webhookUC.ListDeliveries(c.Request().Context(), id, limit)
+	if err != nil {
+		return errorJSON(c, err, http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, deliveries)
+}
@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+// APIKeyHeader carries an API key. Keys are also accepted as bearer tokens.
+const APIKeyHeader = "X-API-Key"
+
+// Authenticate rejects requests without valid credentials with 401. Callers send either
+// an API key (X-API-Key header or "Authorization: Bearer fik_...") or a signed JWT
+// ("Authorization: Bearer <jwt>"). The caller becomes the principal and the actor of
+// the request context. Routes in publicPaths (route patterns like "/swagger/*") are open.
+// It must run after RequestContext, which it overrides the actor of.
+func Authenticate(uc usecase.AuthUsecase, publicPaths ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			for _, p := range publicPaths {
+				if c.Path() == p {
+					return next(c)
+				}
+			}
+
+			req := c.Request()
+			ctx := req.Context()
+			key := req.Header.Get(APIKeyHeader)
+			token, hasBearer := bearerToken(req.Header.Get(echo.HeaderAuthorization))
+			if key == "" && hasBearer && strings.HasPrefix(token, usecase.APIKeyPrefix) {
+				key, hasBearer = token, false
+			}
+
+			var (
+				principal *model.Principal
+				err       error
+			)
+			switch {
+			case key != "":
+				principal, err = uc.AuthenticateAPIKey(ctx, key)
+			case hasBearer:
+				principal, err = uc.AuthenticateToken(ctx, token)
+			default:
+				err = fmt.Errorf("missing credentials: %w", usecase.ErrUnauthenticated)
+			}
+			if err != nil {
+				return unauthorized(c, err)
+			}
+
+			ctx = requestctx.WithPrincipal(ctx, principal)
+			ctx = requestctx.WithActor(ctx, principal.Subject)
+			ctx = requestctx.WithLogger(ctx, requestctx.Logger(ctx).With("user", principal.Subject))
+			c.SetRequest(req.WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// bearerToken extracts the token of an "Authorization: Bearer <token>" header.
+func bearerToken(header string) (string, bool) {
+	const scheme = "Bearer "
+	if len(header) <= len(scheme) || !strings.EqualFold(header[:len(scheme)], scheme) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(scheme):]), true
+}
+
+func unauthorized(c echo.Context, err error) error {
+	if !errors.Is(err, usecase.ErrUnauthenticated) {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="feline-intelligence"`)
+	return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,132 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/lib/logger/sl"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+const (
+	// IdempotencyKeyHeader lets clients retry a POST request without repeating its effect.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from a previous request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// DefaultIdempotencyMaxBodySize is used when no body size limit is configured.
+	DefaultIdempotencyMaxBodySize = 1 << 20
+)
+
+// replayedHeaders are the response headers stored with the body and replayed on retries.
+var replayedHeaders = []string{echo.HeaderLocation, "ETag"}
+
+// Idempotency stores the response of POST requests sent with an Idempotency-Key header
+// and replays it when the request is retried with the same key and the same body.
+// Reusing a key with a different request is rejected with 422, a retry arriving while
+// the first request is still running gets 409. Failed requests (5xx) are not stored, and a
+// key left behind by a request that crashed is taken over by a retry after the lock timeout.
+// Only the given routes are idempotent. Their bodies are buffered and stored, so bodies larger
+// than maxBodySize are rejected with 413.
+func Idempotency(uc usecase.IdempotencyUsecase, maxBodySize int64, routes ...string) echo.MiddlewareFunc {
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultIdempotencyMaxBodySize
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			key := req.Header.Get(IdempotencyKeyHeader)
+			if req.Method != http.MethodPost || key == "" || !slices.Contains(routes, c.Path()) {
+				return next(c)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "idempotency key is too long"})
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(c.Response(), req.Body, maxBodySize))
+			if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+				return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "request body is too large"})
+			}
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := req.Context()
+			rec, err := uc.Begin(ctx, key, requestFingerprint(req, body))
+			switch {
+			case errors.Is(err, usecase.ErrIdempotencyKeyReused):
+				return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+			case errors.Is(err, usecase.ErrIdempotencyInProgress):
+				return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+			case err != nil:
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			}
+			if rec.Completed() {
+				header := c.Response().Header()
+				for name, value := range rec.Headers {
+					header.Set(name, value)
+				}
+				header.Set(IdempotentReplayedHeader, "true")
+				return c.Blob(rec.StatusCode, rec.ContentType, rec.Body)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			err = next(c)
+
+			// The outcome is saved even if the client has gone, it will retry.
+			ctx = context.WithoutCancel(ctx)
+			status := c.Response().Status
+			if err != nil || status >= http.StatusInternalServerError {
+				if releaseErr := uc.Release(ctx, rec); releaseErr != nil {
+					requestctx.Logger(ctx).ErrorContext(ctx, "Failed to release idempotency key", "key", key, sl.Err(releaseErr))
+				}
+				return err
+			}
+			header := c.Response().Header()
+			rec.StatusCode = status
+			rec.ContentType = header.Get(echo.HeaderContentType)
+			rec.Body = recorder.body.Bytes()
+			rec.Headers = make(map[string]string)
+			for _, name := range replayedHeaders {
+				if value := header.Get(name); value != "" {
+					rec.Headers[name] = value
+				}
+			}
+			if completeErr := uc.Complete(ctx, rec); completeErr != nil {
+				requestctx.Logger(ctx).ErrorContext(ctx, "Failed to store response for idempotency key", "key", key, sl.Err(completeErr))
+			}
+			return nil
+		}
+	}
+}
+
+// requestFingerprint identifies the request a key was first used with.
+func requestFingerprint(req *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, req.Method+" "+req.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body written by the handler.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
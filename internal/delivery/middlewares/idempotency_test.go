@@ -0,0 +1,166 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+// fakeIdempotencyUsecase keeps one record per key in memory.
+type fakeIdempotencyUsecase struct {
+	records  map[string]*model.IdempotencyRecord
+	released int
+}
+
+func (u *fakeIdempotencyUsecase) Begin(_ context.Context, key, fingerprint string) (*model.IdempotencyRecord, error) {
+	rec, ok := u.records[key]
+	switch {
+	case !ok:
+		rec = &model.IdempotencyRecord{Key: key, Fingerprint: fingerprint}
+		u.records[key] = rec
+		return rec, nil
+	case rec.Fingerprint != fingerprint:
+		return nil, usecase.ErrIdempotencyKeyReused
+	case !rec.Completed():
+		return nil, usecase.ErrIdempotencyInProgress
+	}
+	return rec, nil
+}
+
+func (u *fakeIdempotencyUsecase) Complete(context.Context, *model.IdempotencyRecord) error {
+	return nil
+}
+
+func (u *fakeIdempotencyUsecase) Release(_ context.Context, rec *model.IdempotencyRecord) error {
+	delete(u.records, rec.Key)
+	u.released++
+	return nil
+}
+
+func (u *fakeIdempotencyUsecase) PurgeExpired(context.Context) (int64, error) { return 0, nil }
+
+func newIdempotentServer(uc usecase.IdempotencyUsecase, calls *int) *echo.Echo {
+	e := echo.New()
+	e.Use(Idempotency(uc, 64, "/cats", "/broken"))
+	e.POST("/cats", func(c echo.Context) error {
+		*calls++
+		c.Response().Header().Set(echo.HeaderLocation, "/cats/1")
+		return c.JSON(http.StatusCreated, map[string]int{"id": *calls})
+	})
+	e.POST("/broken", func(c echo.Context) error {
+		*calls++
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "down"})
+	})
+	e.POST("/import/cats", func(c echo.Context) error {
+		*calls++
+		return c.NoContent(http.StatusNoContent)
+	})
+	return e
+}
+
+func postIdempotent(e *echo.Echo, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	var calls int
+	e := newIdempotentServer(&fakeIdempotencyUsecase{records: make(map[string]*model.IdempotencyRecord)}, &calls)
+
+	first := postIdempotent(e, "/cats", "k1", `{"name":"Tom"}`)
+	retry := postIdempotent(e, "/cats", "k1", `{"name":"Tom"}`)
+
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want once", calls)
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry = %d %s, want %d %s", retry.Code, retry.Body, first.Code, first.Body)
+	}
+	if got := retry.Header().Get(echo.HeaderLocation); got != "/cats/1" {
+		t.Errorf("replayed Location = %q, want /cats/1", got)
+	}
+	if got := retry.Header().Get(IdempotentReplayedHeader); got != "true" {
+		t.Errorf("%s = %q on the retry, want true", IdempotentReplayedHeader, got)
+	}
+	if got := first.Header().Get(IdempotentReplayedHeader); got != "" {
+		t.Errorf("%s = %q on the first response", IdempotentReplayedHeader, got)
+	}
+
+	postIdempotent(e, "/cats", "", `{"name":"Tom"}`)
+	if calls != 2 {
+		t.Errorf("request without a key was not processed")
+	}
+}
+
+func TestIdempotencyRejects(t *testing.T) {
+	var calls int
+	uc := &fakeIdempotencyUsecase{records: map[string]*model.IdempotencyRecord{
+		"running": {Key: "running"},
+	}}
+	e := newIdempotentServer(uc, &calls)
+
+	postIdempotent(e, "/cats", "k1", `{"name":"Tom"}`)
+	if rec := postIdempotent(e, "/cats", "k1", `{"name":"Jerry"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reused key: status = %d, want 422", rec.Code)
+	}
+	uc.records["running"].Fingerprint = requestFingerprint(httptest.NewRequest(http.MethodPost, "/cats", nil), []byte(`{}`))
+	if rec := postIdempotent(e, "/cats", "running", `{}`); rec.Code != http.StatusConflict {
+		t.Errorf("key in progress: status = %d, want 409", rec.Code)
+	}
+	if rec := postIdempotent(e, "/cats", strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("long key: status = %d, want 400", rec.Code)
+	}
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want once", calls)
+	}
+}
+
+func TestIdempotencyReleasesFailedRequests(t *testing.T) {
+	var calls int
+	uc := &fakeIdempotencyUsecase{records: make(map[string]*model.IdempotencyRecord)}
+	e := newIdempotentServer(uc, &calls)
+
+	postIdempotent(e, "/broken", "k1", `{}`)
+	if rec := postIdempotent(e, "/broken", "k1", `{}`); rec.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Error("failed response was replayed")
+	}
+	if calls != 2 || uc.released != 2 {
+		t.Errorf("handler ran %d times and released %d keys, want 2 and 2", calls, uc.released)
+	}
+}
+
+func TestIdempotencyLimitsRoutesAndBodies(t *testing.T) {
+	var calls int
+	uc := &fakeIdempotencyUsecase{records: make(map[string]*model.IdempotencyRecord)}
+	e := newIdempotentServer(uc, &calls)
+
+	large := strings.Repeat("x", 65)
+	for range 2 {
+		if rec := postIdempotent(e, "/import/cats", "k1", large); rec.Code != http.StatusNoContent {
+			t.Errorf("route that is not idempotent: status = %d, want 204", rec.Code)
+		}
+	}
+	if calls != 2 || len(uc.records) != 0 {
+		t.Errorf("route that is not idempotent ran %d times and stored %d keys, want 2 and 0", calls, len(uc.records))
+	}
+
+	if rec := postIdempotent(e, "/cats", "k2", large); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("large body: status = %d, want 413", rec.Code)
+	}
+	if calls != 2 || len(uc.records) != 0 {
+		t.Error("request with a large body was processed")
+	}
+}
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestObserver records handled requests, e.g. as Prometheus metrics.
+type RequestObserver interface {
+	ObserveHTTPRequest(method, route string, status int, duration time.Duration)
+}
+
+// Metrics records the duration of every request by method, route pattern and status.
+// Requests that did not match a route are recorded with the route "unmatched", so
+// arbitrary paths do not create new series.
+func Metrics(m RequestObserver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				// Write the error response now, so its status is recorded.
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" || c.Response().Status == http.StatusNotFound && route == "/*" {
+				route = "unmatched"
+			}
+			m.ObserveHTTPRequest(c.Request().Method, route, c.Response().Status, time.Since(start))
+			return nil
+		}
+	}
+}
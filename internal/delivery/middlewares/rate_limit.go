@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+// RateLimit limits the requests of every client: the API key or the subject of the
+// bearer token of the request, or its IP address when it has no credentials. Limited
+// responses carry the RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and
+// RateLimit-Policy headers, rejected requests get 429 with Retry-After. Requests to
+// exemptPaths (route patterns) are not counted.
+// It must run after Authenticate.
+func RateLimit(uc usecase.RateLimitUsecase, exemptPaths ...string) echo.MiddlewareFunc {
+	return rateLimit(uc, rateLimitClient, exemptPaths)
+}
+
+// RateLimitIP limits the requests of every IP address like RateLimit, before the credentials
+// are checked, so requests with missing or invalid credentials are limited as well.
+// It must run before Authenticate.
+func RateLimitIP(uc usecase.RateLimitUsecase, exemptPaths ...string) echo.MiddlewareFunc {
+	return rateLimit(uc, func(c echo.Context) string { return "addr:" + c.RealIP() }, exemptPaths)
+}
+
+func rateLimit(uc usecase.RateLimitUsecase, client func(c echo.Context) string, exemptPaths []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if slices.Contains(exemptPaths, c.Path()) {
+				return next(c)
+			}
+			decision := uc.Allow(c.Request().Context(), client(c), c.Request().Method, c.Path())
+			if decision == nil {
+				return next(c)
+			}
+
+			h := c.Response().Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(decision.Limit.Burst))
+			h.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+			h.Set("RateLimit-Reset", ceilSeconds(decision.ResetAfter))
+			h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%s;burst=%d",
+				decision.Limit.Requests, ceilSeconds(decision.Limit.Per), decision.Limit.Burst))
+			if !decision.Allowed {
+				h.Set(echo.HeaderRetryAfter, ceilSeconds(decision.RetryAfter))
+				return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "rate limit exceeded"})
+			}
+			return next(c)
+		}
+	}
+}
+
+// rateLimitClient identifies the client a request is counted for by RateLimit. The keys
+// differ from those of RateLimitIP, so both can share a store.
+func rateLimitClient(c echo.Context) string {
+	if p, ok := requestctx.Principal(c.Request().Context()); ok {
+		if p.APIKeyID != 0 {
+			return "key:" + strconv.Itoa(p.APIKeyID)
+		}
+		return "sub:" + p.Subject
+	}
+	return "ip:" + c.RealIP()
+}
+
+// ceilSeconds formats d as whole seconds, rounded up.
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
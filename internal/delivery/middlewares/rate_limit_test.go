@@ -0,0 +1,128 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+)
+
+// fakeRateLimitUsecase rejects the clients in reject and records the clients it counted.
+type fakeRateLimitUsecase struct {
+	reject  map[string]bool
+	clients []string
+}
+
+func (u *fakeRateLimitUsecase) Allow(_ context.Context, client, _, _ string) *model.RateLimitDecision {
+	u.clients = append(u.clients, client)
+	d := &model.RateLimitDecision{
+		Allowed:    !u.reject[client],
+		Limit:      model.RateLimit{Requests: 60, Per: time.Minute, Burst: 10},
+		Remaining:  3,
+		ResetAfter: 6500 * time.Millisecond,
+	}
+	if !d.Allowed {
+		d.Remaining = 0
+		d.RetryAfter = 1500 * time.Millisecond
+	}
+	return d
+}
+
+func serveRateLimited(mw echo.MiddlewareFunc, path string, principal *model.Principal) *httptest.ResponseRecorder {
+	e := echo.New()
+	if principal != nil {
+		e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				c.SetRequest(c.Request().WithContext(requestctx.WithPrincipal(c.Request().Context(), principal)))
+				return next(c)
+			}
+		})
+	}
+	e.Use(mw)
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/cats", ok)
+	e.GET("/healthz", ok)
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitClients(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *model.Principal
+		want      string
+	}{
+		{"anonymous", nil, "ip:192.0.2.1"},
+		{"api key", &model.Principal{Subject: "handler:alice", APIKeyID: 7}, "key:7"},
+		{"token", &model.Principal{Subject: "handler:alice"}, "sub:handler:alice"},
+	}
+	for _, tt := range tests {
+		uc := &fakeRateLimitUsecase{}
+		serveRateLimited(RateLimit(uc), "/cats", tt.principal)
+		if len(uc.clients) != 1 || uc.clients[0] != tt.want {
+			t.Errorf("%s: counted %q, want %q", tt.name, uc.clients, tt.want)
+		}
+	}
+
+	// The per-IP limiter ignores the credentials and has keys of its own.
+	uc := &fakeRateLimitUsecase{}
+	serveRateLimited(RateLimitIP(uc), "/cats", &model.Principal{Subject: "handler:alice", APIKeyID: 7})
+	if len(uc.clients) != 1 || uc.clients[0] != "addr:192.0.2.1" {
+		t.Errorf("per-IP limiter counted %q, want addr:192.0.2.1", uc.clients)
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	rec := serveRateLimited(RateLimit(&fakeRateLimitUsecase{}), "/cats", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	for name, want := range map[string]string{
+		"RateLimit-Limit":     "10",
+		"RateLimit-Remaining": "3",
+		"RateLimit-Reset":     "7",
+		"RateLimit-Policy":    "60;w=60;burst=10",
+	} {
+		if got := rec.Header().Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if got := rec.Header().Get(echo.HeaderRetryAfter); got != "" {
+		t.Errorf("Retry-After = %q on an allowed request", got)
+	}
+
+	uc := &fakeRateLimitUsecase{reject: map[string]bool{"addr:192.0.2.1": true}}
+	rec = serveRateLimited(RateLimitIP(uc), "/cats", nil)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get(echo.HeaderRetryAfter); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+}
+
+func TestRateLimitExemptPaths(t *testing.T) {
+	uc := &fakeRateLimitUsecase{reject: map[string]bool{"addr:192.0.2.1": true, "ip:192.0.2.1": true}}
+	for _, mw := range []echo.MiddlewareFunc{RateLimitIP(uc, "/healthz"), RateLimit(uc, "/healthz")} {
+		rec := serveRateLimited(mw, "/healthz", nil)
+		if rec.Code != http.StatusOK {
+			t.Errorf("exempt path got status %d, want 200", rec.Code)
+		}
+		if rec.Header().Get("RateLimit-Limit") != "" {
+			t.Error("exempt path got rate limit headers")
+		}
+	}
+	if len(uc.clients) != 0 {
+		t.Errorf("exempt path was counted for %q", uc.clients)
+	}
+}
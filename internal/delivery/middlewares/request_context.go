@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+)
+
+// ActorHeader identifies the caller when authentication is disabled.
+// With authentication enabled the actor is the subject of the credentials.
+const ActorHeader = "X-Actor"
+
+// RequestContext copies the request ID, the caller identity and the If-Match precondition
+// into the request context, so usecases can read them without depending on echo.
+func RequestContext() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := req.Context()
+
+			requestID := req.Header.Get(echo.HeaderXRequestID)
+			if requestID == "" {
+				requestID = c.Response().Header().Get(echo.HeaderXRequestID)
+			}
+			ctx = requestctx.WithRequestID(ctx, requestID)
+			ctx = requestctx.WithActor(ctx, req.Header.Get(ActorHeader))
+			if etags := parseIfMatch(req.Header.Get("If-Match")); len(etags) > 0 {
+				ctx = requestctx.WithIfMatch(ctx, etags)
+			}
+
+			c.SetRequest(req.WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// parseIfMatch splits the If-Match header into entity tags, e.g. `"3", "4"` or `*`.
+func parseIfMatch(header string) []string {
+	var etags []string
+	for _, etag := range strings.Split(header, ",") {
+		if etag = strings.TrimSpace(etag); etag != "" {
+			etags = append(etags, etag)
+		}
+	}
+	return etags
+}
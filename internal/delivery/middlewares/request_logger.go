@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+)
+
+// RequestLogger attaches a logger carrying the request ID, the method and the route to the
+// request context and writes an access log line once the request is handled. Authenticate
+// adds the caller to the logger. It must run after the request ID middleware.
+func RequestLogger(logger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+
+			requestID := req.Header.Get(echo.HeaderXRequestID)
+			if requestID == "" {
+				requestID = c.Response().Header().Get(echo.HeaderXRequestID)
+			}
+			requestLogger := logger.With("request_id", requestID, "method", req.Method, "route", c.Path())
+			c.SetRequest(req.WithContext(requestctx.WithLogger(req.Context(), requestLogger)))
+
+			if err := next(c); err != nil {
+				// Write the error response now, so its status is logged.
+				c.Error(err)
+			}
+
+			res := c.Response()
+			ctx := c.Request().Context()
+			attrs := []any{
+				"path", req.URL.Path,
+				"status", res.Status,
+				"latency", time.Since(start).String(),
+				"bytes_out", res.Size,
+				"remote_ip", c.RealIP(),
+			}
+			if p, ok := requestctx.Principal(ctx); ok {
+				attrs = append(attrs, "user", p.Subject)
+			}
+
+			level := slog.LevelInfo
+			switch {
+			case res.Status >= 500:
+				level = slog.LevelError
+			case res.Status >= 400:
+				level = slog.LevelWarn
+			}
+			requestLogger.Log(ctx, level, "Request handled", attrs...)
+			return nil
+		}
+	}
+}
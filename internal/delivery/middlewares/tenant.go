@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/usecase"
+)
+
+// TenantHeader selects the agency of a request for callers not bound to one.
+const TenantHeader = "X-Tenant-ID"
+
+// Tenant resolves the agency the request acts within from the credentials or the
+// X-Tenant-ID header and stores it in the request context, which scopes all queries.
+// It must run after Authenticate.
+func Tenant(uc usecase.AgencyUsecase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := req.Context()
+
+			var requested *int
+			if header := req.Header.Get(TenantHeader); header != "" {
+				id, err := strconv.Atoi(header)
+				if err != nil || id <= 0 {
+					return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid " + TenantHeader + " header"})
+				}
+				requested = &id
+			}
+
+			tenantID, err := uc.ResolveTenant(ctx, requested)
+			if err != nil {
+				status := http.StatusInternalServerError
+				switch {
+				case errors.Is(err, usecase.ErrForbidden):
+					status = http.StatusForbidden
+				case errors.Is(err, usecase.ErrNotFound):
+					status = http.StatusNotFound
+				}
+				return c.JSON(status, map[string]string{"error": err.Error()})
+			}
+
+			ctx = requestctx.WithTenant(ctx, tenantID)
+			ctx = requestctx.WithLogger(ctx, requestctx.Logger(ctx).With("tenant_id", tenantID))
+			c.SetRequest(req.WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// TenantConnection runs the queries of the request on a database connection restricted to
+// its agency, released once the request is done. scope is pg.Transactor.ScopeRequest.
+// It must run after Tenant.
+func TenantConnection(scope func(ctx context.Context) (context.Context, func())) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, release := scope(c.Request().Context())
+			defer release()
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
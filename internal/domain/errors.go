@@ -0,0 +1,11 @@
+package domain
+
+import "errors"
+
+// ErrRuleViolation is returned when the database rejects a change because it breaks
+// a business rule enforced by a trigger or a constraint (e.g. more than 3 targets per mission).
+var ErrRuleViolation = errors.New("business rule violation")
+
+// ErrVersionConflict is returned by repositories when a row was changed by someone else
+// after it had been read, i.e. its version no longer matches the expected one.
+var ErrVersionConflict = errors.New("version conflict")
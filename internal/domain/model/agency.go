@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// DefaultAgencyID is the agency of requests that do not name one and of the data
+// created before agencies existed.
+const DefaultAgencyID = 1
+
+// Agency is a tenant: an independent spy agency with its own cats and missions.
+type Agency struct {
+	ID   int    `json:"id" example:"1"`
+	Name string `json:"name" example:"Default agency"`
+	// MaxTargetsPerMission is enforced by the database when targets are added.
+	MaxTargetsPerMission int       `json:"max_targets_per_mission" example:"3"`
+	CreatedAt            time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
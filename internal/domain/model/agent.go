@@ -0,0 +1,8 @@
+package model
+
+// AgentProfile describes the caller acting as a spy cat.
+type AgentProfile struct {
+	Subject string   `json:"subject" example:"cat:tom"`
+	Roles   []string `json:"roles" example:"agent"`
+	Cat     Cat      `json:"cat"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Reasons why a cat stopped working on a mission.
+const (
+	UnassignReasonReassigned       = "reassigned"
+	UnassignReasonUnassigned       = "unassigned"
+	UnassignReasonMissionCompleted = "mission_completed"
+	UnassignReasonCatDeleted       = "cat_deleted"
+)
+
+// MissionAssignment is a period during which a cat was assigned to a mission.
+type MissionAssignment struct {
+	ID           int        `json:"id" example:"1"`
+	MissionID    int        `json:"mission_id" example:"1"`
+	CatID        int        `json:"cat_id" example:"1"`
+	AssignedAt   time.Time  `json:"assigned_at" example:"2023-01-01T00:00:00Z"`
+	UnassignedAt *time.Time `json:"unassigned_at,omitempty" example:"2023-01-05T00:00:00Z"`
+	Reason       *string    `json:"reason,omitempty" example:"mission_completed" enums:"reassigned,unassigned,mission_completed,cat_deleted"`
+}
+
+// Active reports whether the cat is still assigned to the mission.
+func (a MissionAssignment) Active() bool {
+	return a.UnassignedAt == nil
+}
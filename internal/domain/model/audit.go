@@ -0,0 +1,55 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEvent describes a single mutating operation performed on an entity.
+type AuditEvent struct {
+	ID         int64           `json:"id" example:"1"`
+	Actor      string          `json:"actor" example:"handler-42"`
+	Action     string          `json:"action" example:"cat.update_salary"`
+	EntityType string          `json:"entity_type" example:"cat"`
+	EntityID   int             `json:"entity_id" example:"1"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	RequestID  string          `json:"request_id" example:"b1d4c1b2-6f55-4d0e-9a1e-1f3c3c0d4a11"`
+	CreatedAt  time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// AuditFilter narrows down the list of audit events. Zero values are ignored.
+type AuditFilter struct {
+	EntityType string
+	EntityID   int
+	Actor      string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
+// Entity types recorded in the audit log.
+const (
+	AuditEntityCat     = "cat"
+	AuditEntityMission = "mission"
+	AuditEntityTarget  = "target"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditActionCatCreate          = "cat.create"
+	AuditActionCatUpdateSalary    = "cat.update_salary"
+	AuditActionCatDelete          = "cat.delete"
+	AuditActionCatRequestSalary   = "cat.request_salary_change"
+	AuditActionCatApproveSalary   = "cat.approve_salary_change"
+	AuditActionCatRejectSalary    = "cat.reject_salary_change"
+	AuditActionMissionCreate      = "mission.create"
+	AuditActionMissionDelete      = "mission.delete"
+	AuditActionMissionComplete    = "mission.complete"
+	AuditActionMissionAssignCat   = "mission.assign_cat"
+	AuditActionMissionUnassignCat = "mission.unassign_cat"
+	AuditActionTargetAdd          = "target.add"
+	AuditActionTargetDelete       = "target.delete"
+	AuditActionTargetComplete     = "target.complete"
+	AuditActionTargetUpdateNotes  = "target.update_notes"
+)
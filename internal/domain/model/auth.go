@@ -0,0 +1,71 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// Authentication methods of a Principal.
+const (
+	AuthMethodAPIKey = "api_key"
+	AuthMethodJWT    = "jwt"
+)
+
+// Roles of callers. Admins may do everything, handlers run cats and missions, agents are
+// the cats themselves working on their own missions and auditors may read everything.
+const (
+	RoleAdmin   = "admin"
+	RoleHandler = "handler"
+	RoleAgent   = "agent"
+	RoleAuditor = "auditor"
+)
+
+// Roles lists all known roles.
+var Roles = []string{RoleAdmin, RoleHandler, RoleAgent, RoleAuditor}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// Subject identifies the caller, it is recorded as the actor of audit entries and events.
+	Subject string   `json:"subject" example:"handler:alice"`
+	Method  string   `json:"method" example:"jwt" enums:"api_key,jwt"`
+	Roles   []string `json:"roles" example:"handler"`
+	// CatID is the cat an agent acts as.
+	CatID *int `json:"cat_id,omitempty" example:"1"`
+	// TenantID binds the caller to an agency, platform-wide callers have none.
+	TenantID *int `json:"tenant_id,omitempty" example:"1"`
+	// APIKeyID is the key the caller authenticated with, if any.
+	APIKeyID int `json:"api_key_id,omitempty" example:"1"`
+}
+
+// HasRole reports whether the caller has the role.
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// APIKey authenticates its Subject. Only the hash of the key is stored, Prefix is kept
+// to tell keys apart.
+type APIKey struct {
+	ID         int        `json:"id" example:"1"`
+	Name       string     `json:"name" example:"ops-dashboard"`
+	Prefix     string     `json:"prefix" example:"fik_3f9a1c2b"`
+	KeyHash    string     `json:"-"`
+	Subject    string     `json:"subject" example:"service:ops-dashboard"`
+	Roles      []string   `json:"roles" example:"handler"`
+	CatID      *int       `json:"cat_id,omitempty" example:"1"`
+	TenantID   *int       `json:"tenant_id,omitempty" example:"1"`
+	CreatedAt  time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2023-01-02T00:00:00Z"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" example:"2024-01-01T00:00:00Z"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" example:"2023-06-01T00:00:00Z"`
+}
+
+// Usable reports whether the key may authenticate at the given time.
+func (k *APIKey) Usable(at time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || at.Before(*k.ExpiresAt))
+}
+
+// IssuedAPIKey is a newly issued API key together with the key itself, which is never shown again.
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key" example:"fik_3f9a1c2b_8d0c..."`
+}
@@ -0,0 +1,46 @@
+package model
+
+// Target batch operation kinds.
+const (
+	TargetOpAdd         = "add"
+	TargetOpComplete    = "complete"
+	TargetOpUpdateNotes = "update_notes"
+	TargetOpDelete      = "delete"
+)
+
+// Target batch operation result statuses.
+const (
+	TargetOpStatusOK         = "ok"
+	TargetOpStatusFailed     = "failed"
+	TargetOpStatusRolledBack = "rolled_back"
+)
+
+// TargetOperation is a single operation of a targets batch.
+// add uses MissionID, Name, Country and Notes; the other operations use TargetID (and Notes for update_notes).
+// Version, when set, is the version the target must still have for the operation to apply.
+type TargetOperation struct {
+	Op        string `json:"op" example:"complete" enums:"add,complete,update_notes,delete"`
+	TargetID  int    `json:"target_id,omitempty" example:"1"`
+	MissionID int    `json:"mission_id,omitempty" example:"1"`
+	Name      string `json:"name,omitempty" example:"Target Alpha"`
+	Country   string `json:"country,omitempty" example:"Meowland"`
+	Notes     string `json:"notes,omitempty" example:"Highly guarded"`
+	Version   *int   `json:"version,omitempty" example:"1"`
+}
+
+// TargetOperationResult is the outcome of a single batch operation.
+type TargetOperationResult struct {
+	Index    int    `json:"index" example:"0"`
+	Op       string `json:"op" example:"complete"`
+	Status   string `json:"status" example:"ok" enums:"ok,failed,rolled_back"`
+	TargetID int    `json:"target_id,omitempty" example:"1"`
+	Error    string `json:"error,omitempty" example:"business rule violation: Mission 1 already has maximum number of targets (3)"`
+	// RuleViolation is set when the operation was rejected by a database trigger or constraint.
+	RuleViolation bool `json:"rule_violation,omitempty" example:"false"`
+}
+
+// TargetBatchResult reports a batch of target operations, committed only if every operation succeeded.
+type TargetBatchResult struct {
+	Committed bool                    `json:"committed" example:"true"`
+	Results   []TargetOperationResult `json:"results"`
+}
@@ -0,0 +1,17 @@
+package model
+
+// CandidateScore is the breakdown of a candidate score, each component is already weighted.
+type CandidateScore struct {
+	Experience     float64 `json:"experience" example:"15"`
+	CountrySuccess float64 `json:"country_success" example:"12"`
+	BreedTraits    float64 `json:"breed_traits" example:"20"`
+	Cost           float64 `json:"cost" example:"7.5"`
+}
+
+// Candidate is an available cat ranked for a mission.
+type Candidate struct {
+	Cat          Cat            `json:"cat"`
+	Score        float64        `json:"score" example:"54.5"`
+	Breakdown    CandidateScore `json:"breakdown"`
+	Explanations []string       `json:"explanations" example:"5 years of experience"`
+}
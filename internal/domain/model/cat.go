@@ -7,6 +7,7 @@ type Cat struct {
 	Name              string    `json:"name" example:"Whiskers"`
 	YearsOfExperience int       `json:"years_of_experience" example:"5"`
 	Breed             string    `json:"breed" example:"Siamese"`
-	Salary            float64   `json:"salary" example:"1000.0"`
+	Salary            Money     `json:"salary"`
+	Version           int       `json:"version" example:"1"`
 	CreatedAt         time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
 }
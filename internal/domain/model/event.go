@@ -0,0 +1,55 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DomainEvent is a fact about a change of a cat, a mission or a target, published to other systems.
+// Payload holds the aggregate after the change, or before it for deletions and unassignments,
+// so consumers still see which cat left the mission.
+type DomainEvent struct {
+	ID            int64           `json:"id" example:"1"`
+	Type          string          `json:"type" example:"MissionCompleted"`
+	AggregateType string          `json:"aggregate_type" example:"mission"`
+	AggregateID   int             `json:"aggregate_id" example:"1"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"`
+	Actor         string          `json:"actor" example:"handler-42"`
+	RequestID     string          `json:"request_id,omitempty" example:"b1d4c1b2-6f55-4d0e-9a1e-1f3c3c0d4a11"`
+	OccurredAt    time.Time       `json:"occurred_at" example:"2023-01-01T00:00:00Z"`
+	// TenantID is the agency of the aggregate, events are only delivered within it.
+	TenantID int `json:"tenant_id" example:"1"`
+	// Attempts is the number of failed deliveries of the event from the outbox.
+	Attempts int `json:"-"`
+}
+
+// Domain event types.
+const (
+	EventCatCreated         = "CatCreated"
+	EventCatSalaryChanged   = "CatSalaryChanged"
+	EventCatDeleted         = "CatDeleted"
+	EventMissionCreated     = "MissionCreated"
+	EventMissionAssigned    = "MissionAssigned"
+	EventMissionUnassigned  = "MissionUnassigned"
+	EventMissionCompleted   = "MissionCompleted"
+	EventMissionDeleted     = "MissionDeleted"
+	EventTargetAdded        = "TargetAdded"
+	EventTargetCompleted    = "TargetCompleted"
+	EventTargetNotesUpdated = "TargetNotesUpdated"
+	EventTargetDeleted      = "TargetDeleted"
+)
+
+// EventTypes lists all domain event types.
+var EventTypes = []string{
+	EventCatCreated, EventCatSalaryChanged, EventCatDeleted,
+	EventMissionCreated, EventMissionAssigned, EventMissionUnassigned, EventMissionCompleted, EventMissionDeleted,
+	EventTargetAdded, EventTargetCompleted, EventTargetNotesUpdated, EventTargetDeleted,
+}
+
+// StreamEvent is a domain event with the mission and the cat it concerns, so live streams can be
+// filtered by them. For target events CatID is the cat of the mission when the event was read.
+type StreamEvent struct {
+	DomainEvent
+	MissionID *int `json:"mission_id,omitempty" example:"1"`
+	CatID     *int `json:"cat_id,omitempty" example:"1"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// ExportFilter selects rows created or last modified in [Since, Until), so consecutive ranges also pick up
+// rows changed after they were first exported. Audit events never change and are selected by creation
+// time. Zero values are ignored.
+type ExportFilter struct {
+	Since time.Time
+	Until time.Time
+}
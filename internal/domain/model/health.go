@@ -0,0 +1,29 @@
+package model
+
+// Health check statuses.
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+// HealthCheck is the outcome of checking a dependency.
+type HealthCheck struct {
+	Name   string `json:"name" example:"postgres"`
+	Status string `json:"status" example:"ok"`
+	Error  string `json:"error,omitempty" example:"context deadline exceeded"`
+}
+
+// Readiness tells whether the application can serve requests. Status is HealthStatusOK
+// when every check passed and the application is not shutting down.
+type Readiness struct {
+	Status string        `json:"status" example:"ok"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+// BuildInfo identifies the running build.
+type BuildInfo struct {
+	Name      string `json:"name" example:"feline-intelligence"`
+	Version   string `json:"version" example:"1.0.0"`
+	Commit    string `json:"commit" example:"3f2c1a9"`
+	BuildTime string `json:"build_time" example:"2024-01-01T00:00:00Z"`
+}
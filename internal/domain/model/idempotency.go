@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// IdempotencyRecord is the stored outcome of a request sent with an Idempotency-Key header.
+// Keys are scoped to the agency and the actor, StatusCode is zero while the request is still being processed.
+type IdempotencyRecord struct {
+	Actor       string
+	Key         string
+	Fingerprint string
+	StatusCode  int
+	ContentType string
+	// Headers are the response headers replayed with the body, e.g. ETag and Location.
+	Headers map[string]string
+	Body    []byte
+	// CreatedAt identifies the reservation, a request only completes or releases its own one.
+	CreatedAt time.Time
+	// LockedUntil is when a retry may take over the key of a request that never completed.
+	LockedUntil time.Time
+	ExpiresAt   time.Time
+}
+
+// Completed reports whether the response of the request has been stored.
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
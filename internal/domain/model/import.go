@@ -0,0 +1,33 @@
+package model
+
+// Import modes.
+const (
+	// ImportModeAtomic imports all rows in a single transaction or none of them.
+	ImportModeAtomic = "atomic"
+	// ImportModeBestEffort imports every valid row on its own and reports the failed ones.
+	ImportModeBestEffort = "best_effort"
+)
+
+// Import row statuses.
+const (
+	ImportRowCreated    = "created"
+	ImportRowFailed     = "failed"
+	ImportRowRolledBack = "rolled_back"
+)
+
+// ImportRowResult is the outcome of importing a single row (or a CSV group of rows for missions).
+type ImportRowResult struct {
+	Row    int    `json:"row" example:"2"`
+	Status string `json:"status" example:"created" enums:"created,failed,rolled_back"`
+	ID     *int   `json:"id,omitempty" example:"17"`
+	Error  string `json:"error,omitempty" example:"breed 'Dragon' is not a valid cat breed"`
+}
+
+// ImportReport summarizes a bulk import.
+type ImportReport struct {
+	Mode      string            `json:"mode" example:"best_effort"`
+	Total     int               `json:"total" example:"3"`
+	Succeeded int               `json:"succeeded" example:"2"`
+	Failed    int               `json:"failed" example:"1"`
+	Results   []ImportRowResult `json:"results"`
+}
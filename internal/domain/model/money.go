@@ -0,0 +1,139 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// amountScale is the number of fractional digits kept by Amount, matching NUMERIC(10,2).
+	amountScale = 2
+	// MaxAmount is the largest amount NUMERIC(10,2) holds, 99999999.99.
+	MaxAmount Amount = 99_999_999_99
+)
+
+var errInvalidAmount = errors.New("invalid amount")
+
+// Amount is an exact decimal amount stored in minor units (cents).
+// It is serialized to JSON as a string ("1000.50") to avoid float precision loss.
+type Amount int64
+
+// ParseAmount parses a decimal string like "1000", "1000.5" or "-3.25".
+// More than two fractional digits are rejected instead of being rounded, as are amounts
+// beyond MaxAmount.
+func ParseAmount(s string) (Amount, error) {
+	orig := strings.TrimSpace(s)
+	neg := strings.HasPrefix(orig, "-")
+	s = orig
+	if neg || strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, fmt.Errorf("%w: %q", errInvalidAmount, orig)
+	}
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > amountScale {
+		return 0, fmt.Errorf("%w: %q has more than %d fractional digits", errInvalidAmount, orig, amountScale)
+	}
+	fracPart += strings.Repeat("0", amountScale-len(fracPart))
+	if intPart == "" {
+		intPart = "0"
+	}
+
+	v, err := strconv.ParseInt(intPart+fracPart, 10, 64)
+	if err != nil || strings.ContainsAny(intPart+fracPart, "+-") {
+		return 0, fmt.Errorf("%w: %q", errInvalidAmount, orig)
+	}
+	if v > int64(MaxAmount) {
+		return 0, fmt.Errorf("%w: %q exceeds %s", errInvalidAmount, orig, MaxAmount)
+	}
+	if neg {
+		v = -v
+	}
+	return Amount(v), nil
+}
+
+// String formats the amount with exactly two fractional digits.
+func (a Amount) String() string {
+	sign := ""
+	v := int64(a)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
+
+// Float64 returns an approximate value of the amount, for statistics only.
+func (a Amount) Float64() float64 {
+	return float64(a) / 100
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON accepts both a string ("1000.50") and a JSON number (1000.5).
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	v, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Scan reads a Postgres NUMERIC value without going through float64.
+func (a *Amount) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	case int64:
+		*a = Amount(v * 100)
+		return nil
+	case nil:
+		*a = 0
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Amount", src)
+	}
+}
+
+func (a *Amount) scanString(s string) error {
+	v, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Value writes the amount as a decimal string, which Postgres casts to NUMERIC exactly.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Money is an amount in a particular currency (ISO 4217 code).
+type Money struct {
+	Amount   Amount `json:"amount" swaggertype:"string" example:"1000.00"`
+	Currency string `json:"currency" example:"USD"`
+}
+
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
+
+// DefaultCurrency is used when no allowed currencies are configured.
+const DefaultCurrency = "USD"
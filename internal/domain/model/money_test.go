@@ -0,0 +1,123 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+	}{
+		{"0", 0},
+		{"1000", 100000},
+		{"1000.5", 100050},
+		{"1000.50", 100050},
+		{"1000.500", 100050},
+		{"0.01", 1},
+		{".5", 50},
+		{"5.", 500},
+		{"-3.25", -325},
+		{"+3.25", 325},
+		{" 12.30 ", 1230},
+		{"99999999.99", MaxAmount},
+		{"-99999999.99", -MaxAmount},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.in)
+		if err != nil {
+			t.Errorf("ParseAmount(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseAmountRejectsInvalid(t *testing.T) {
+	for _, in := range []string{
+		"", " ", "-", ".", "abc", "1,5", "1e3", "1.2.3", "1.234", "0.001",
+		"--1", "+-1", "-+5", "++5", "1-", "1.-5", "99999999999999999999",
+		"100000000", "99999999.991", "-100000000",
+	} {
+		if got, err := ParseAmount(in); !errors.Is(err, errInvalidAmount) {
+			t.Errorf("ParseAmount(%q) = %d, %v, want errInvalidAmount", in, got, err)
+		}
+	}
+}
+
+func TestAmountString(t *testing.T) {
+	tests := []struct {
+		in   Amount
+		want string
+	}{
+		{0, "0.00"},
+		{1, "0.01"},
+		{100050, "1000.50"},
+		{-5, "-0.05"},
+		{-325, "-3.25"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	b, err := json.Marshal(Money{Amount: 100050, Currency: "USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"amount":"1000.50","currency":"USD"}`; string(b) != want {
+		t.Errorf("Marshal = %s, want %s", b, want)
+	}
+
+	for _, in := range []string{`"1000.50"`, `1000.5`, `"1000.5"`} {
+		var a Amount
+		if err := json.Unmarshal([]byte(in), &a); err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %v", in, err)
+			continue
+		}
+		if a != 100050 {
+			t.Errorf("Unmarshal(%s) = %d, want 100050", in, a)
+		}
+	}
+
+	for _, in := range []string{`1000.505`, `1000000000`, `"-+5"`} {
+		var a Amount
+		if err := json.Unmarshal([]byte(in), &a); !errors.Is(err, errInvalidAmount) {
+			t.Errorf("Unmarshal(%s) = %d, %v, want errInvalidAmount", in, a, err)
+		}
+	}
+}
+
+func TestAmountScan(t *testing.T) {
+	tests := []struct {
+		src  any
+		want Amount
+	}{
+		{[]byte("1000.50"), 100050},
+		{"12.3", 1230},
+		{int64(7), 700},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		a := Amount(42)
+		if err := a.Scan(tt.src); err != nil {
+			t.Errorf("Scan(%#v) returned error: %v", tt.src, err)
+			continue
+		}
+		if a != tt.want {
+			t.Errorf("Scan(%#v) = %d, want %d", tt.src, a, tt.want)
+		}
+	}
+
+	var a Amount
+	if err := a.Scan(1.5); err == nil {
+		t.Error("Scan(float64) succeeded, want an error")
+	}
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// RateLimit is a token bucket: it allows Requests per Per on average and bursts of up to
+// Burst requests. A limit without requests does not limit anything.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+// Unlimited reports whether the limit lets every request through.
+func (l RateLimit) Unlimited() bool {
+	return l.Requests <= 0 || l.Per <= 0
+}
+
+// Rate is the number of tokens added to the bucket per second.
+func (l RateLimit) Rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// RateLimitDecision is the outcome of counting a request against its limit.
+type RateLimitDecision struct {
+	Allowed bool
+	Limit   RateLimit
+	// Remaining is the number of requests that may follow immediately.
+	Remaining int
+	// ResetAfter is the time until the bucket is full again.
+	ResetAfter time.Duration
+	// RetryAfter is the time until the next request is allowed, zero when it is allowed now.
+	RetryAfter time.Duration
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// Payroll grouping dimensions.
+const (
+	PayrollGroupBreed      = "breed"
+	PayrollGroupExperience = "experience"
+)
+
+// PayrollEntry is the salary of a single cat used to build the payroll report.
+type PayrollEntry struct {
+	CatID             int
+	Breed             string
+	YearsOfExperience int
+	Salary            Money
+}
+
+// PayrollFilter configures the payroll report.
+type PayrollFilter struct {
+	// GroupBy contains PayrollGroupBreed and/or PayrollGroupExperience. Empty means totals only.
+	GroupBy []string
+	// AsOf computes salaries effective on this date from the salary history. Nil means current salaries.
+	AsOf *time.Time
+}
+
+// PayrollGroup aggregates salaries of cats sharing the same group key and currency.
+type PayrollGroup struct {
+	Breed          string `json:"breed,omitempty" example:"Siamese"`
+	ExperienceBand string `json:"experience_band,omitempty" example:"2-4"`
+	Currency       string `json:"currency" example:"USD"`
+	Headcount      int    `json:"headcount" example:"3"`
+	Total          Amount `json:"total" swaggertype:"string" example:"3600.00"`
+	Average        Amount `json:"average" swaggertype:"string" example:"1200.00"`
+	Median         Amount `json:"median" swaggertype:"string" example:"1100.00"`
+}
+
+// PayrollReport is the agency payroll, grouped as requested and totalled per currency.
+type PayrollReport struct {
+	AsOf        *time.Time     `json:"as_of,omitempty" example:"2023-01-31T00:00:00Z"`
+	GeneratedAt time.Time      `json:"generated_at" example:"2023-02-01T09:00:00Z"`
+	GroupBy     []string       `json:"group_by" example:"breed,experience"`
+	Groups      []PayrollGroup `json:"groups"`
+	Totals      []PayrollGroup `json:"totals"`
+}
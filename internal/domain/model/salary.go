@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Salary change statuses.
+const (
+	SalaryChangeApplied  = "applied"
+	SalaryChangePending  = "pending"
+	SalaryChangeRejected = "rejected"
+)
+
+// SalaryChange is an entry of the cat's salary history.
+type SalaryChange struct {
+	ID             int        `json:"id" example:"1"`
+	CatID          int        `json:"cat_id" example:"1"`
+	PreviousSalary Money      `json:"previous_salary"`
+	NewSalary      Money      `json:"new_salary"`
+	Reason         string     `json:"reason" example:"Successful mission in Meowland"`
+	Status         string     `json:"status" example:"applied" enums:"applied,pending,rejected"`
+	EffectiveDate  time.Time  `json:"effective_date" example:"2023-01-01T00:00:00Z"`
+	RequestedBy    string     `json:"requested_by" example:"handler-42"`
+	DecidedBy      *string    `json:"decided_by,omitempty" example:"admin"`
+	DecidedAt      *time.Time `json:"decided_at,omitempty" example:"2023-01-02T00:00:00Z"`
+	CreatedAt      time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
@@ -0,0 +1,39 @@
+package model
+
+// Leaderboard sort keys.
+const (
+	StatsSortCompletedMissions = "completed_missions"
+	StatsSortTargetsCompleted  = "targets_completed"
+	StatsSortAvgTimeToComplete = "avg_time_to_complete"
+	StatsSortCountries         = "countries"
+)
+
+// CatStats describes the performance of a cat derived from its missions.
+type CatStats struct {
+	CatID             int    `json:"cat_id" example:"1"`
+	Name              string `json:"name" example:"Whiskers"`
+	CompletedMissions int    `json:"completed_missions" example:"4"`
+	// TargetsCompleted credits every target to the cat assigned to the mission when it was completed,
+	// including targets of missions the cat was later reassigned from.
+	TargetsCompleted int `json:"targets_completed" example:"9"`
+	// AvgTimeToCompleteSeconds is the average time between creation and completion of the cat's missions.
+	AvgTimeToCompleteSeconds *float64 `json:"avg_time_to_complete_seconds" example:"86400"`
+	ActiveMissionID          *int     `json:"active_mission_id" example:"7"`
+	// Countries are the countries of the targets the cat completed.
+	Countries []string `json:"countries" example:"Meowland,Purrsia"`
+}
+
+// LeaderboardEntry is a ranked CatStats.
+type LeaderboardEntry struct {
+	Rank int `json:"rank" example:"1"`
+	CatStats
+}
+
+// AgencyGauges are the current workload counts of an agency, exported as metrics.
+type AgencyGauges struct {
+	TenantID           int
+	ActiveMissions     int
+	UnassignedMissions int
+	// IdleCats counts the cats without an active mission.
+	IdleCats int
+}
@@ -9,5 +9,6 @@ type Target struct {
 	Country   string    `json:"country" example:"Meowland"`
 	Notes     string    `json:"notes" example:"Highly guarded"`
 	Complete  bool      `json:"complete" example:"false"`
+	Version   int       `json:"version" example:"1"`
 	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
 }
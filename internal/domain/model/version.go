@@ -0,0 +1,8 @@
+package model
+
+import "strconv"
+
+// ETag formats the row version of an entity as a strong entity tag.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookSubscription sends the domain events of its agency to an HTTP endpoint. An empty
+// EventTypes subscribes to all events. Secret signs the payloads and is only shown on creation.
+type WebhookSubscription struct {
+	ID                  int        `json:"id" example:"1"`
+	TenantID            int        `json:"tenant_id" example:"1"`
+	URL                 string     `json:"url" example:"https://ops.example.com/hooks/missions"`
+	Secret              string     `json:"secret,omitempty" example:"whsec_3f9a..."`
+	EventTypes          []string   `json:"event_types" example:"MissionCompleted,TargetCompleted"`
+	Active              bool       `json:"active" example:"true"`
+	ConsecutiveFailures int        `json:"consecutive_failures" example:"0"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty" example:"2023-01-05T00:00:00Z"`
+	CreatedAt           time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// Wants reports whether the subscription is interested in the event type.
+func (s *WebhookSubscription) Wants(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is the delivery of one event to one subscription, with the outcome of the last attempt.
+type WebhookDelivery struct {
+	ID             int64           `json:"id" example:"1"`
+	SubscriptionID int             `json:"subscription_id" example:"1"`
+	TenantID       int             `json:"tenant_id" example:"1"`
+	EventID        int64           `json:"event_id" example:"1"`
+	EventType      string          `json:"event_type" example:"MissionCompleted"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status" example:"succeeded" enums:"pending,succeeded,failed"`
+	Attempts       int             `json:"attempts" example:"1"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at" example:"2023-01-01T00:00:00Z"`
+	LastStatusCode *int            `json:"last_status_code,omitempty" example:"200"`
+	LastError      string          `json:"last_error,omitempty" example:"context deadline exceeded"`
+	CreatedAt      time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" example:"2023-01-01T00:00:01Z"`
+}
+
+// Webhook delivery statuses.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
@@ -1,30 +1,223 @@
 package domain
 
-import "github.com/alextotalk/feline-intelligence/internal/domain/model"
+import (
+	"context"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+)
+
+// Transactor runs fn inside a transaction. Repositories called with the context passed to fn
+// take part in that transaction.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
 
 // CatRepository
 type CatRepository interface {
-	Create(cat *model.Cat) error
-	GetByID(id int) (*model.Cat, error)
-	GetAll() ([]model.Cat, error)
-	Update(cat *model.Cat) error
-	Delete(id int) error
+	Create(ctx context.Context, cat *model.Cat) error
+	GetByID(ctx context.Context, id int) (*model.Cat, error)
+	GetAll(ctx context.Context) ([]model.Cat, error)
+	// GetAvailable returns cats without an active mission.
+	GetAvailable(ctx context.Context) ([]model.Cat, error)
+	// Update fails with ErrVersionConflict when the cat was changed since it had been read.
+	Update(ctx context.Context, cat *model.Cat) error
+	Delete(ctx context.Context, id int) error
 }
 
 // MissionRepository
 type MissionRepository interface {
-	Create(mission *model.Mission) error
-	GetByID(id int) (*model.Mission, error)
-	GetAll() ([]model.Mission, error)
-	Update(mission *model.Mission) error
-	Delete(id int) error
-	AssignCat(missionID, catID int) error
+	Create(ctx context.Context, mission *model.Mission) error
+	GetByID(ctx context.Context, id int) (*model.Mission, error)
+	GetAll(ctx context.Context) ([]model.Mission, error)
+	// GetActiveByCat returns the not completed mission of the cat, nil, nil when it has none.
+	GetActiveByCat(ctx context.Context, catID int) (*model.Mission, error)
+	// Update fails with ErrVersionConflict when the mission was changed since it had been read.
+	Update(ctx context.Context, mission *model.Mission) error
+	Delete(ctx context.Context, id int) error
 }
 
 // TargetRepository
 type TargetRepository interface {
-	AddToMission(target *model.Target) error
-	Update(target *model.Target) error
-	Delete(id int) error
-	GetByID(id int) (*model.Target, error) // За потреби
+	AddToMission(ctx context.Context, target *model.Target) error
+	// Update fails with ErrVersionConflict when the target was changed since it had been read.
+	Update(ctx context.Context, target *model.Target) error
+	Delete(ctx context.Context, id int) error
+	GetByID(ctx context.Context, id int) (*model.Target, error) // За потреби
+}
+
+// AuditRepository
+type AuditRepository interface {
+	Create(ctx context.Context, event *model.AuditEvent) error
+	List(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error)
+}
+
+// SalaryHistoryRepository
+type SalaryHistoryRepository interface {
+	Create(ctx context.Context, change *model.SalaryChange) error
+	GetByID(ctx context.Context, id int) (*model.SalaryChange, error)
+	ListByCat(ctx context.Context, catID int) ([]model.SalaryChange, error)
+	GetPendingByCat(ctx context.Context, catID int) (*model.SalaryChange, error)
+	UpdateStatus(ctx context.Context, change *model.SalaryChange) error
+}
+
+// ReportRepository
+type ReportRepository interface {
+	// PayrollEntries returns salaries of cats effective on asOf, or current salaries when asOf is nil.
+	PayrollEntries(ctx context.Context, asOf *time.Time) ([]model.PayrollEntry, error)
+}
+
+// StatsRepository
+type StatsRepository interface {
+	CatStats(ctx context.Context, catID int) (*model.CatStats, error)
+	// Leaderboard returns stats of all cats ordered by the sortBy metric (one of model.StatsSort*), best first.
+	Leaderboard(ctx context.Context, sortBy string, limit int) ([]model.CatStats, error)
+	// CompletedTargetsIn counts completed targets per cat (cat ID -> count) in the given countries,
+	// crediting the cat assigned to the mission when the target was completed.
+	CompletedTargetsIn(ctx context.Context, countries []string) (map[int]int, error)
+	// AgencyGauges returns the workload counts of every agency, it is not scoped by tenant.
+	AgencyGauges(ctx context.Context) ([]model.AgencyGauges, error)
+}
+
+// AssignmentRepository
+type AssignmentRepository interface {
+	// Open starts a new assignment of the cat to the mission.
+	Open(ctx context.Context, assignment *model.MissionAssignment) error
+	// CloseByMission ends the open assignment of the mission, if any.
+	CloseByMission(ctx context.Context, missionID int, reason string) error
+	// CloseByCat ends all open assignments of the cat.
+	CloseByCat(ctx context.Context, catID int, reason string) error
+	ListByMission(ctx context.Context, missionID int) ([]model.MissionAssignment, error)
+	ListByCat(ctx context.Context, catID int) ([]model.MissionAssignment, error)
+}
+
+// ExportRepository streams whole tables row by row, so memory stays flat for large tables.
+// Streaming stops at the first error returned by fn.
+type ExportRepository interface {
+	StreamCats(ctx context.Context, filter model.ExportFilter, fn func(model.Cat) error) error
+	StreamMissions(ctx context.Context, filter model.ExportFilter, fn func(model.Mission) error) error
+	StreamAuditEvents(ctx context.Context, filter model.ExportFilter, fn func(model.AuditEvent) error) error
+}
+
+// IdempotencyRepository stores responses of idempotent requests within the agency of ctx.
+type IdempotencyRepository interface {
+	// Reserve creates the record unless a live one exists for the same actor and key. An expired
+	// record is replaced, as is an uncompleted record of the same request past its lock.
+	// It reports whether the record was created.
+	Reserve(ctx context.Context, rec *model.IdempotencyRecord) (bool, error)
+	// Get returns nil, nil when there is no record.
+	Get(ctx context.Context, actor, key string) (*model.IdempotencyRecord, error)
+	// Complete and Delete only affect the reservation made at rec.CreatedAt, not one taken over by a retry.
+	Complete(ctx context.Context, rec *model.IdempotencyRecord) error
+	Delete(ctx context.Context, rec *model.IdempotencyRecord) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// OutboxRepository stores domain events until they are delivered to the sinks.
+type OutboxRepository interface {
+	Add(ctx context.Context, event *model.DomainEvent) error
+	// ClaimPending locks up to limit events due for delivery, skipping the ones locked by
+	// other dispatchers. It must be called inside a transaction.
+	ClaimPending(ctx context.Context, limit int) ([]model.DomainEvent, error)
+	MarkDispatched(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error
+}
+
+// EventSink receives domain events from the outbox dispatcher. Delivery is at-least-once,
+// so a sink may see the same event (identified by its ID) more than once.
+type EventSink interface {
+	Name() string
+	Handle(ctx context.Context, event model.DomainEvent) error
+}
+
+// WebhookRepository stores webhook subscriptions and their delivery log. Subscriptions are
+// read and changed within the agency of ctx, the deliverer claims the deliveries of all agencies.
+type WebhookRepository interface {
+	Create(ctx context.Context, sub *model.WebhookSubscription) error
+	GetByID(ctx context.Context, id int) (*model.WebhookSubscription, error)
+	List(ctx context.Context) ([]model.WebhookSubscription, error)
+	Update(ctx context.Context, sub *model.WebhookSubscription) error
+	Delete(ctx context.Context, id int) error
+	// ListSubscribed returns the active subscriptions of the agency interested in the event type.
+	ListSubscribed(ctx context.Context, tenantID int, eventType string) ([]model.WebhookSubscription, error)
+	// RecordFailure counts a failed delivery and disables the subscription once disableAfter
+	// consecutive deliveries failed. It reports whether the subscription got disabled.
+	RecordFailure(ctx context.Context, id int, disableAfter int) (bool, error)
+	ResetFailures(ctx context.Context, id int) error
+
+	// EnqueueDelivery ignores events already queued for the subscription.
+	EnqueueDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	// ClaimDueDeliveries claims up to limit due pending deliveries of active subscriptions for
+	// lease by moving their next attempt to the end of the lease, which the returned deliveries
+	// carry in NextAttemptAt. Other deliverers skip them until then, so they are claimed again
+	// when the deliverer dies before recording the outcome.
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error)
+	// UpdateDelivery records the outcome of an attempt of a delivery claimed until claimedUntil.
+	// It reports false when the lease expired and the delivery was claimed again meanwhile.
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery, claimedUntil time.Time) (bool, error)
+	ListDeliveries(ctx context.Context, subscriptionID, limit int) ([]model.WebhookDelivery, error)
+}
+
+// WebhookSender posts a delivery to the subscription endpoint. statusCode is zero when
+// no response was received.
+type WebhookSender interface {
+	Send(ctx context.Context, sub model.WebhookSubscription, delivery model.WebhookDelivery) (statusCode int, err error)
+}
+
+// EventStreamRepository reads domain events of the outbox for live streams.
+type EventStreamRepository interface {
+	// GetByID returns nil, nil when there is no such event.
+	GetByID(ctx context.Context, id int64) (*model.StreamEvent, error)
+	// ListAfter returns up to limit events with IDs greater than afterID, oldest first.
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]model.StreamEvent, error)
+	// ListLatest returns the last limit events, oldest first.
+	ListLatest(ctx context.Context, limit int) ([]model.StreamEvent, error)
+}
+
+// EventListener reports domain events committed by any replica of the application.
+type EventListener interface {
+	// Listen calls fn with the ID of every new event until ctx is done. After the connection
+	// was lost and restored it calls fn with 0, as notifications may have been missed meanwhile.
+	Listen(ctx context.Context, fn func(eventID int64)) error
+}
+
+// APIKeyRepository stores hashed API keys.
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *model.APIKey) error
+	// GetByID and GetByHash return nil, nil when there is no such key.
+	GetByID(ctx context.Context, id int) (*model.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+	// List returns the keys of the agency, or all keys when tenantID is nil.
+	List(ctx context.Context, tenantID *int) ([]model.APIKey, error)
+	Revoke(ctx context.Context, id int, at time.Time) error
+	// TouchLastUsed records the use of the key. It may skip the write when the key was used moments ago.
+	TouchLastUsed(ctx context.Context, id int, at time.Time) error
+}
+
+// TokenVerifier checks signed bearer tokens and returns the caller they were issued to.
+type TokenVerifier interface {
+	Verify(token string) (*model.Principal, error)
+}
+
+// AgencyRepository stores agencies (tenants). Agencies are not scoped by tenant themselves.
+type AgencyRepository interface {
+	Create(ctx context.Context, agency *model.Agency) error
+	// GetByID returns nil, nil when there is no such agency.
+	GetByID(ctx context.Context, id int) (*model.Agency, error)
+	List(ctx context.Context) ([]model.Agency, error)
+	Update(ctx context.Context, agency *model.Agency) error
+}
+
+// RateLimitStore keeps the token buckets of rate limits.
+type RateLimitStore interface {
+	// Take refills the bucket of key according to limit and takes a token when one is available.
+	// It reports whether a token was taken and how many tokens are left.
+	Take(ctx context.Context, key string, limit model.RateLimit) (allowed bool, tokens float64, err error)
+}
+
+// HealthRepository checks the database.
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	// SchemaVersion returns the version of the last applied migration and whether it failed half-way.
+	SchemaVersion(ctx context.Context) (version int, dirty bool, err error)
 }
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+)
+
+// JWTConfig holds the keys bearer tokens are signed with. At least one key is required,
+// tokens signed with an algorithm that has no key are rejected.
+type JWTConfig struct {
+	// HS256Secret is the shared secret of HS256 tokens.
+	HS256Secret string
+	// RS256PublicKeyFile is the PEM encoded RSA public key of RS256 tokens.
+	RS256PublicKeyFile string
+	// Issuer and Audience, when set, must match the iss and aud claims.
+	Issuer   string
+	Audience string
+}
+
+// claims are the claims of bearer tokens: the registered ones plus the roles of the
+// caller, for agents the cat they act as and the agency the caller is bound to.
+type claims struct {
+	jwt.RegisteredClaims
+	Roles    []string `json:"roles"`
+	CatID    *int     `json:"cat_id,omitempty"`
+	TenantID *int     `json:"tenant_id,omitempty"`
+}
+
+type jwtVerifier struct {
+	hmacKey []byte
+	rsaKey  any
+	parser  *jwt.Parser
+}
+
+// NewJWTVerifier creates a domain.TokenVerifier of HS256 and RS256 signed tokens.
+// Tokens must carry the sub and exp claims, roles, cat_id and tenant_id are optional.
+func NewJWTVerifier(cfg JWTConfig) (domain.TokenVerifier, error) {
+	v := &jwtVerifier{}
+	var methods []string
+	if cfg.HS256Secret != "" {
+		v.hmacKey = []byte(cfg.HS256Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.RS256PublicKeyFile != "" {
+		pem, err := os.ReadFile(cfg.RS256PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read RS256 public key: %w", err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RS256 public key: %w", err)
+		}
+		v.rsaKey = key
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("no JWT signing key configured")
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+	return v, nil
+}
+
+func (v *jwtVerifier) Verify(token string) (*model.Principal, error) {
+	var c claims
+	_, err := v.parser.ParseWithClaims(token, &c, func(t *jwt.Token) (any, error) {
+		// The parser already restricts the algorithm to the configured ones.
+		if t.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+			return v.hmacKey, nil
+		}
+		return v.rsaKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	return &model.Principal{Subject: c.Subject, Method: model.AuthMethodJWT, Roles: c.Roles, CatID: c.CatID, TenantID: c.TenantID}, nil
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, exp time.Time) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "handler:alice", ExpiresAt: jwt.NewNumericDate(exp)},
+		Roles:            []string{"handler"},
+	}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// writePublicKey writes the PEM encoded public key of key to a temporary file.
+func writePublicKey(t *testing.T, key *rsa.PrivateKey) (string, []byte) {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	path := filepath.Join(t.TempDir(), "jwt.pub")
+	if err := os.WriteFile(path, pemBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path, pemBytes
+}
+
+func TestVerifyHS256(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewJWTVerifier(JWTConfig{HS256Secret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hour := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"HS256", sign(t, jwt.SigningMethodHS256, []byte(testSecret), hour), true},
+		{"other secret", sign(t, jwt.SigningMethodHS256, []byte("other"), hour), false},
+		{"expired", sign(t, jwt.SigningMethodHS256, []byte(testSecret), time.Now().Add(-time.Minute)), false},
+		{"HS512", sign(t, jwt.SigningMethodHS512, []byte(testSecret), hour), false},
+		{"RS256 without a configured key", sign(t, jwt.SigningMethodRS256, rsaKey, hour), false},
+		{"none", sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := v.Verify(tt.token)
+			if tt.valid != (err == nil) {
+				t.Fatalf("err = %v, want valid = %t", err, tt.valid)
+			}
+			if tt.valid && principal.Subject != "handler:alice" {
+				t.Errorf("subject = %q, want handler:alice", principal.Subject)
+			}
+		})
+	}
+}
+
+func TestVerifyRS256RejectsHMACWithPublicKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, publicPEM := writePublicKey(t, rsaKey)
+	v, err := NewJWTVerifier(JWTConfig{RS256PublicKeyFile: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hour := time.Now().Add(time.Hour)
+
+	if _, err := v.Verify(sign(t, jwt.SigningMethodRS256, rsaKey, hour)); err != nil {
+		t.Errorf("RS256 token rejected: %v", err)
+	}
+	// The public key is no secret, an HS256 token signed with it must not pass as RS256.
+	if _, err := v.Verify(sign(t, jwt.SigningMethodHS256, publicPEM, hour)); err == nil {
+		t.Error("HS256 token signed with the public key was accepted")
+	}
+}
@@ -5,60 +5,144 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
+// catalogueTTL is how long the breed catalogue is cached before it is fetched again.
+const catalogueTTL = time.Hour
+
+// Breed is a breed from thecatapi catalogue. Traits are rated from 1 to 5.
+type Breed struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Origin           string `json:"origin"`
+	Temperament      string `json:"temperament"`
+	Intelligence     int    `json:"intelligence"`
+	Adaptability     int    `json:"adaptability"`
+	EnergyLevel      int    `json:"energy_level"`
+	StrangerFriendly int    `json:"stranger_friendly"`
+}
+
 // CatAPI describes methods of interaction with thecatapi.
 type CatAPI interface {
 	IsBreedValid(ctx context.Context, breedName string) (bool, error)
+	// Breeds returns the breed catalogue, cached for catalogueTTL.
+	Breeds(ctx context.Context) ([]Breed, error)
+	// Ping checks that thecatapi responds, without using the quota of the API key.
+	Ping(ctx context.Context) error
 }
 
+// Observer is notified of every request to thecatapi, e.g. to record metrics.
+// err is nil when the request succeeded.
+type Observer func(operation string, duration time.Duration, err error)
+
 type catAPI struct {
 	httpClient *http.Client
 	apiURL     string
 	apiKey     string
+	observe    Observer
+
+	mu          sync.Mutex
+	breeds      []Breed
+	refreshedAt time.Time
 }
 
-// NewCatAPI Creates an instance for working with thecatapi.
-func NewCatAPI(apiURL, apiKey string) CatAPI {
+// NewCatAPI Creates an instance for working with thecatapi. observe may be nil.
+func NewCatAPI(apiURL, apiKey string, observe Observer) CatAPI {
+	if observe == nil {
+		observe = func(string, time.Duration, error) {}
+	}
 	return &catAPI{
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		// The transport traces requests and propagates the trace context to thecatapi.
+		httpClient: &http.Client{Timeout: 10 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
 		apiURL:     apiURL,
 		apiKey:     apiKey,
+		observe:    observe,
 	}
 }
 
 func (c *catAPI) IsBreedValid(ctx context.Context, breedName string) (bool, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+"/v1/breeds", nil)
+	breeds, err := c.Breeds(ctx)
 	if err != nil {
 		return false, err
 	}
 
+	for _, b := range breeds {
+		if b.Name == breedName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *catAPI) Breeds(ctx context.Context) ([]Breed, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.breeds != nil && time.Since(c.refreshedAt) < catalogueTTL {
+		return c.breeds, nil
+	}
+
+	breeds, err := c.fetchBreeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.breeds = breeds
+	c.refreshedAt = time.Now()
+	return breeds, nil
+}
+
+func (c *catAPI) Ping(ctx context.Context) (err error) {
+	defer func(start time.Time) {
+		c.observe("ping", time.Since(start), err)
+	}(time.Now())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.apiURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("catapi: unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *catAPI) fetchBreeds(ctx context.Context) (breeds []Breed, err error) {
+	defer func(start time.Time) {
+		c.observe("breeds", time.Since(start), err)
+	}(time.Now())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+"/v1/breeds", nil)
+	if err != nil {
+		return nil, err
+	}
+
 	if c.apiKey != "" {
 		req.Header.Set("x-api-key", c.apiKey)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("catapi: unexpected status code %d", resp.StatusCode)
+		return nil, fmt.Errorf("catapi: unexpected status code %d", resp.StatusCode)
 	}
 
-	var data []struct {
-		Name string `json:"name"`
-	}
+	var data []Breed
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return false, err
+		return nil, err
 	}
-
-	for _, b := range data {
-		if b.Name == breedName {
-			return true, nil
-		}
-	}
-	return false, nil
+	return data, nil
 }
@@ -0,0 +1,33 @@
+package eventsink
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+)
+
+// LogSink writes every domain event to the application log.
+type LogSink struct {
+	logger *slog.Logger
+}
+
+func NewLogSink(logger *slog.Logger) domain.EventSink {
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Handle(ctx context.Context, e model.DomainEvent) error {
+	s.logger.InfoContext(ctx, "Domain event",
+		"event_id", e.ID,
+		"type", e.Type,
+		"aggregate_type", e.AggregateType,
+		"aggregate_id", e.AggregateID,
+		"actor", e.Actor,
+	)
+	return nil
+}
@@ -0,0 +1,51 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+)
+
+// WebhookSink queues a delivery of the event for every subscription of its agency interested in it.
+// The deliveries are written in the transaction of the outbox dispatcher and sent by
+// the webhook deliverer, so slow endpoints do not hold up the other sinks.
+type WebhookSink struct {
+	webhookRepo domain.WebhookRepository
+}
+
+func NewWebhookSink(wr domain.WebhookRepository) domain.EventSink {
+	return &WebhookSink{webhookRepo: wr}
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhooks"
+}
+
+func (s *WebhookSink) Handle(ctx context.Context, e model.DomainEvent) error {
+	subs, err := s.webhookRepo.ListSubscribed(ctx, e.TenantID, e.Type)
+	if err != nil {
+		return err
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		err := s.webhookRepo.EnqueueDelivery(ctx, &model.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			TenantID:       e.TenantID,
+			EventID:        e.ID,
+			EventType:      e.Type,
+			Payload:        payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/lib/logger/sl"
+)
+
+const namespace = "feline"
+
+// gaugesTimeout limits the queries of the business gauges on a scrape.
+const gaugesTimeout = 5 * time.Second
+
+// Metrics is the Prometheus registry of the application.
+type Metrics struct {
+	registry       *prometheus.Registry
+	httpDuration   *prometheus.HistogramVec
+	catAPIDuration *prometheus.HistogramVec
+	catAPIErrors   *prometheus.CounterVec
+}
+
+// New registers the runtime, sql.DB pool and business metrics. The business gauges are
+// queried from statsRepo on every scrape.
+func New(db *sql.DB, statsRepo domain.StatsRepository, logger *slog.Logger) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		catAPIDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "catapi_request_duration_seconds",
+			Help:      "Duration of requests to thecatapi by operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		catAPIErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "catapi_errors_total",
+			Help:      "Failed requests to thecatapi by operation.",
+		}, []string{"operation"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(db, "postgres"),
+		m.httpDuration,
+		m.catAPIDuration,
+		m.catAPIErrors,
+		&gaugesCollector{statsRepo: statsRepo, logger: logger.With("component", "metrics")},
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveHTTPRequest records a handled request. route is the route pattern, e.g. "/cats/:id".
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	m.httpDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// ObserveCatAPI records a request to thecatapi, it is a catapi.Observer.
+func (m *Metrics) ObserveCatAPI(operation string, duration time.Duration, err error) {
+	m.catAPIDuration.WithLabelValues(operation).Observe(duration.Seconds())
+	if err != nil {
+		m.catAPIErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+var (
+	activeMissionsDesc = prometheus.NewDesc(namespace+"_active_missions",
+		"Missions that are not completed, by agency.", []string{"tenant_id"}, nil)
+	unassignedMissionsDesc = prometheus.NewDesc(namespace+"_unassigned_missions",
+		"Missions that are not completed and have no cat assigned, by agency.", []string{"tenant_id"}, nil)
+	idleCatsDesc = prometheus.NewDesc(namespace+"_idle_cats",
+		"Cats without an active mission, by agency.", []string{"tenant_id"}, nil)
+)
+
+// gaugesCollector queries the business gauges when metrics are scraped.
+type gaugesCollector struct {
+	statsRepo domain.StatsRepository
+	logger    *slog.Logger
+}
+
+func (c *gaugesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeMissionsDesc
+	ch <- unassignedMissionsDesc
+	ch <- idleCatsDesc
+}
+
+func (c *gaugesCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), gaugesTimeout)
+	defer cancel()
+
+	gauges, err := c.statsRepo.AgencyGauges(ctx)
+	if err != nil {
+		// The other metrics are still useful, the gauges are just missing from this scrape.
+		c.logger.Error("Failed to collect business gauges", sl.Err(err))
+		return
+	}
+	for _, g := range gauges {
+		tenant := strconv.Itoa(g.TenantID)
+		ch <- prometheus.MustNewConstMetric(activeMissionsDesc, prometheus.GaugeValue, float64(g.ActiveMissions), tenant)
+		ch <- prometheus.MustNewConstMetric(unassignedMissionsDesc, prometheus.GaugeValue, float64(g.UnassignedMissions), tenant)
+		ch <- prometheus.MustNewConstMetric(idleCatsDesc, prometheus.GaugeValue, float64(g.IdleCats), tenant)
+	}
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+)
+
+// sweepInterval is how often buckets that filled up again are dropped.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket holds Burst tokens again, it can be dropped from then on.
+	full time.Time
+}
+
+// memoryStore keeps buckets in the memory of the process, so every replica counts on its own.
+type memoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates a domain.RateLimitStore local to the process.
+func NewMemoryStore() domain.RateLimitStore {
+	return &memoryStore{buckets: make(map[string]*bucket), lastSweep: time.Now()}
+}
+
+func (s *memoryStore) Take(_ context.Context, key string, limit model.RateLimit) (bool, float64, error) {
+	now := time.Now()
+	burst, rate := float64(limit.Burst), limit.Rate()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for k, b := range s.buckets {
+			if !now.Before(b.full) {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	b.full = now.Add(time.Duration((burst - b.tokens) / rate * float64(time.Second)))
+	return allowed, b.tokens, nil
+}
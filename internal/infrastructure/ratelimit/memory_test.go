@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+)
+
+func TestMemoryStoreAllowsBurstThenRejects(t *testing.T) {
+	s := NewMemoryStore()
+	limit := model.RateLimit{Requests: 60, Per: time.Minute, Burst: 3}
+
+	for i := range 3 {
+		allowed, tokens, err := s.Take(context.Background(), "client", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !allowed {
+			t.Fatalf("request %d rejected within the burst", i+1)
+		}
+		if want := float64(2 - i); tokens < want || tokens > want+0.1 {
+			t.Errorf("request %d left %.2f tokens, want about %.0f", i+1, tokens, want)
+		}
+	}
+	if allowed, _, _ := s.Take(context.Background(), "client", limit); allowed {
+		t.Error("request beyond the burst was allowed")
+	}
+	if allowed, _, _ := s.Take(context.Background(), "other", limit); !allowed {
+		t.Error("request of another client was rejected")
+	}
+}
+
+func TestMemoryStoreRefills(t *testing.T) {
+	s := NewMemoryStore().(*memoryStore)
+	limit := model.RateLimit{Requests: 60, Per: time.Minute, Burst: 2}
+
+	for range 2 {
+		s.Take(context.Background(), "client", limit)
+	}
+	// One token per second, move the last refill 1.5s back.
+	s.buckets["client"].updated = s.buckets["client"].updated.Add(-1500 * time.Millisecond)
+
+	allowed, tokens, _ := s.Take(context.Background(), "client", limit)
+	if !allowed {
+		t.Fatal("request rejected after the bucket refilled")
+	}
+	if tokens < 0.4 || tokens > 0.6 {
+		t.Errorf("%.2f tokens left, want about 0.5", tokens)
+	}
+
+	// The bucket never holds more than the burst.
+	s.buckets["client"].updated = s.buckets["client"].updated.Add(-time.Hour)
+	if _, tokens, _ := s.Take(context.Background(), "client", limit); tokens > 1.01 {
+		t.Errorf("%.2f tokens left after a long pause, want at most burst-1", tokens)
+	}
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	s := NewMemoryStore().(*memoryStore)
+	limit := model.RateLimit{Requests: 60, Per: time.Minute, Burst: 5}
+
+	s.Take(context.Background(), "idle", limit)
+	s.buckets["idle"].full = time.Now().Add(-time.Second)
+	s.lastSweep = time.Now().Add(-sweepInterval)
+
+	s.Take(context.Background(), "active", limit)
+	if _, ok := s.buckets["idle"]; ok {
+		t.Error("full bucket was not swept")
+	}
+	if _, ok := s.buckets["active"]; !ok {
+		t.Error("bucket in use was swept")
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
+)
+
+type AgencyPgRepository struct {
+	db *sql.DB
+}
+
+func NewAgencyPgRepository(db *sql.DB) domain.AgencyRepository {
+	return &AgencyPgRepository{db: db}
+}
+
+func (r *AgencyPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+const agencyColumns = `id, name, max_targets_per_mission, created_at`
+
+func scanAgency(row rowScanner) (*model.Agency, error) {
+	var a model.Agency
+	if err := row.Scan(&a.ID, &a.Name, &a.MaxTargetsPerMission, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AgencyPgRepository) Create(ctx context.Context, a *model.Agency) error {
+	query := `
+        INSERT INTO agencies (name, max_targets_per_mission)
+        VALUES ($1, $2)
+        RETURNING id, created_at
+    `
+	// Duplicate names violate a unique constraint, reported as a rule violation.
+	return pg.TranslateError(r.conn(ctx).QueryRowContext(ctx, query, a.Name, a.MaxTargetsPerMission).Scan(&a.ID, &a.CreatedAt))
+}
+
+func (r *AgencyPgRepository) GetByID(ctx context.Context, id int) (*model.Agency, error) {
+	a, err := scanAgency(r.conn(ctx).QueryRowContext(ctx,
+		`SELECT `+agencyColumns+` FROM agencies WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return a, err
+}
+
+func (r *AgencyPgRepository) List(ctx context.Context) ([]model.Agency, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `SELECT `+agencyColumns+` FROM agencies ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var agencies []model.Agency
+	for rows.Next() {
+		a, err := scanAgency(rows)
+		if err != nil {
+			return nil, err
+		}
+		agencies = append(agencies, *a)
+	}
+	return agencies, rows.Err()
+}
+
+func (r *AgencyPgRepository) Update(ctx context.Context, a *model.Agency) error {
+	_, err := r.conn(ctx).ExecContext(ctx,
+		`UPDATE agencies SET name = $1, max_targets_per_mission = $2 WHERE id = $3`,
+		a.Name, a.MaxTargetsPerMission, a.ID)
+	return pg.TranslateError(err)
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
+)
+
+type APIKeyPgRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyPgRepository(db *sql.DB) domain.APIKeyRepository {
+	return &APIKeyPgRepository{db: db}
+}
+
+func (r *APIKeyPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+const apiKeyColumns = `id, name, prefix, key_hash, subject, roles, cat_id, tenant_id, created_at, last_used_at, expires_at, revoked_at`
+
+func scanAPIKey(row rowScanner) (*model.APIKey, error) {
+	var k model.APIKey
+	err := row.Scan(&k.ID, &k.Name, &k.Prefix, &k.KeyHash, &k.Subject, pq.Array(&k.Roles), &k.CatID, &k.TenantID, &k.CreatedAt,
+		&k.LastUsedAt, &k.ExpiresAt, &k.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *APIKeyPgRepository) Create(ctx context.Context, k *model.APIKey) error {
+	query := `
+        INSERT INTO api_keys (name, prefix, key_hash, subject, roles, cat_id, tenant_id, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id, created_at
+    `
+	return r.conn(ctx).QueryRowContext(ctx, query, k.Name, k.Prefix, k.KeyHash, k.Subject,
+		pq.Array(k.Roles), k.CatID, k.TenantID, k.ExpiresAt).
+		Scan(&k.ID, &k.CreatedAt)
+}
+
+func (r *APIKeyPgRepository) GetByID(ctx context.Context, id int) (*model.APIKey, error) {
+	k, err := scanAPIKey(r.conn(ctx).QueryRowContext(ctx,
+		`SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return k, err
+}
+
+func (r *APIKeyPgRepository) GetByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	k, err := scanAPIKey(r.conn(ctx).QueryRowContext(ctx,
+		`SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1`, keyHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return k, err
+}
+
+func (r *APIKeyPgRepository) List(ctx context.Context, tenantID *int) ([]model.APIKey, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+        SELECT `+apiKeyColumns+`
+        FROM api_keys
+        WHERE $1::int IS NULL OR tenant_id = $1
+        ORDER BY id
+    `, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []model.APIKey
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *k)
+	}
+	return keys, rows.Err()
+}
+
+func (r *APIKeyPgRepository) Revoke(ctx context.Context, id int, at time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx,
+		`UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`, at, id)
+	return err
+}
+
+func (r *APIKeyPgRepository) TouchLastUsed(ctx context.Context, id int, at time.Time) error {
+	// Written at most once a minute, so busy keys do not cost a write per request.
+	query := `
+        UPDATE api_keys
+        SET last_used_at = $1
+        WHERE id = $2 AND (last_used_at IS NULL OR last_used_at < $1 - INTERVAL '1 minute')
+    `
+	_, err := r.conn(ctx).ExecContext(ctx, query, at, id)
+	return err
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
+)
+
+type AssignmentPgRepository struct {
+	db *sql.DB
+}
+
+func NewAssignmentPgRepository(db *sql.DB) domain.AssignmentRepository {
+	return &AssignmentPgRepository{db: db}
+}
+
+func (r *AssignmentPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+func (r *AssignmentPgRepository) Open(ctx context.Context, a *model.MissionAssignment) error {
+	query := `
+        INSERT INTO mission_assignments (mission_id, cat_id, tenant_id)
+        VALUES ($1, $2, $3)
+        RETURNING id, assigned_at
+    `
+	return r.conn(ctx).QueryRowContext(ctx, query, a.MissionID, a.CatID, tenantID(ctx)).
+		Scan(&a.ID, &a.AssignedAt)
+}
+
+func (r *AssignmentPgRepository) CloseByMission(ctx context.Context, missionID int, reason string) error {
+	query := `
+        UPDATE mission_assignments
+        SET unassigned_at = now(), reason = $1
+        WHERE mission_id = $2 AND unassigned_at IS NULL AND tenant_id = $3
+    `
+	_, err := r.conn(ctx).ExecContext(ctx, query, reason, missionID, tenantID(ctx))
+	return err
+}
+
+func (r *AssignmentPgRepository) CloseByCat(ctx context.Context, catID int, reason string) error {
+	query := `
+        UPDATE mission_assignments
+        SET unassigned_at = now(), reason = $1
+        WHERE cat_id = $2 AND unassigned_at IS NULL AND tenant_id = $3
+    `
+	_, err := r.conn(ctx).ExecContext(ctx, query, reason, catID, tenantID(ctx))
+	return err
+}
+
+func (r *AssignmentPgRepository) ListByMission(ctx context.Context, missionID int) ([]model.MissionAssignment, error) {
+	return r.list(ctx, `WHERE mission_id = $1 AND tenant_id = $2`, missionID, tenantID(ctx))
+}
+
+func (r *AssignmentPgRepository) ListByCat(ctx context.Context, catID int) ([]model.MissionAssignment, error) {
+	return r.list(ctx, `WHERE cat_id = $1 AND tenant_id = $2`, catID, tenantID(ctx))
+}
+
+func (r *AssignmentPgRepository) list(ctx context.Context, where string, args ...any) ([]model.MissionAssignment, error) {
+	query := `
+        SELECT id, mission_id, cat_id, assigned_at, unassigned_at, reason
+        FROM mission_assignments
+    ` + where + `
+        ORDER BY assigned_at DESC, id DESC
+    `
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []model.MissionAssignment
+	for rows.Next() {
+		var a model.MissionAssignment
+		if err := rows.Scan(&a.ID, &a.MissionID, &a.CatID, &a.AssignedAt, &a.UnassignedAt, &a.Reason); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments, rows.Err()
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
+)
+
+const defaultAuditLimit = 100
+
+type AuditPgRepository struct {
+	db *sql.DB
+}
+
+func NewAuditPgRepository(db *sql.DB) domain.AuditRepository {
+	return &AuditPgRepository{db: db}
+}
+
+func (r *AuditPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+func (r *AuditPgRepository) Create(ctx context.Context, e *model.AuditEvent) error {
+	query := `
+        INSERT INTO audit_events (actor, action, entity_type, entity_id, before, after, request_id, tenant_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id, created_at
+    `
+	return r.conn(ctx).QueryRowContext(ctx, query, e.Actor, e.Action, e.EntityType, e.EntityID,
+		nullableJSON(e.Before), nullableJSON(e.After), e.RequestID, tenantID(ctx)).
+		Scan(&e.ID, &e.CreatedAt)
+}
+
+func (r *AuditPgRepository) List(ctx context.Context, f model.AuditFilter) ([]model.AuditEvent, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	add("tenant_id = $%d", tenantID(ctx))
+	if f.EntityType != "" {
+		add("entity_type = $%d", f.EntityType)
+	}
+	if f.EntityID != 0 {
+		add("entity_id = $%d", f.EntityID)
+	}
+	if f.Actor != "" {
+		add("actor = $%d", f.Actor)
+	}
+	if !f.From.IsZero() {
+		add("created_at >= $%d", f.From)
+	}
+	if !f.To.IsZero() {
+		add("created_at < $%d", f.To)
+	}
+
+	query := `
+        SELECT id, actor, action, entity_type, entity_id, before, after, COALESCE(request_id, ''), created_at
+        FROM audit_events
+    ` + " WHERE " + strings.Join(conds, " AND ")
+	limit := f.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []model.AuditEvent
+	for rows.Next() {
+		var (
+			e             model.AuditEvent
+			before, after []byte
+		)
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Action, &e.EntityType, &e.EntityID,
+			&before, &after, &e.RequestID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.Before, e.After = before, after
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// nullableJSON stores empty snapshots as SQL NULL instead of an invalid JSONB value.
+func nullableJSON(b []byte) any {
+	if len(b) == 0 {
+		return nil
+	}
+	return string(b)
+}
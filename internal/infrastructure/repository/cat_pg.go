@@ -1,11 +1,13 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
 	"github.com/alextotalk/feline-intelligence/internal/domain"
 	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
 )
 
 type CatPgRepository struct {
@@ -16,25 +18,30 @@ func NewCatPgRepository(db *sql.DB) domain.CatRepository {
 	return &CatPgRepository{db: db}
 }
 
-func (r *CatPgRepository) Create(cat *model.Cat) error {
+func (r *CatPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+func (r *CatPgRepository) Create(ctx context.Context, cat *model.Cat) error {
 	query := `
-        INSERT INTO spy_cats (name, years_of_experience, breed, salary)
-        VALUES ($1, $2, $3, $4)
-        RETURNING id, created_at
+        INSERT INTO spy_cats (name, years_of_experience, breed, salary, currency, tenant_id)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, version, created_at
     `
-	return r.db.QueryRow(query, cat.Name, cat.YearsOfExperience, cat.Breed, cat.Salary).
-		Scan(&cat.ID, &cat.CreatedAt)
+	return r.conn(ctx).QueryRowContext(ctx, query, cat.Name, cat.YearsOfExperience, cat.Breed,
+		cat.Salary.Amount, cat.Salary.Currency, tenantID(ctx)).
+		Scan(&cat.ID, &cat.Version, &cat.CreatedAt)
 }
 
-func (r *CatPgRepository) GetByID(id int) (*model.Cat, error) {
+func (r *CatPgRepository) GetByID(ctx context.Context, id int) (*model.Cat, error) {
 	cat := model.Cat{}
 	query := `
-        SELECT id, name, years_of_experience, breed, salary, created_at
+        SELECT id, name, years_of_experience, breed, salary, currency, version, created_at
         FROM spy_cats
-        WHERE id = $1
+        WHERE id = $1 AND tenant_id = $2
     `
-	err := r.db.QueryRow(query, id).
-		Scan(&cat.ID, &cat.Name, &cat.YearsOfExperience, &cat.Breed, &cat.Salary, &cat.CreatedAt)
+	err := r.conn(ctx).QueryRowContext(ctx, query, id, tenantID(ctx)).
+		Scan(&cat.ID, &cat.Name, &cat.YearsOfExperience, &cat.Breed, &cat.Salary.Amount, &cat.Salary.Currency, &cat.Version, &cat.CreatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	} else if err != nil {
@@ -43,11 +50,27 @@ func (r *CatPgRepository) GetByID(id int) (*model.Cat, error) {
 	return &cat, nil
 }
 
-func (r *CatPgRepository) GetAll() ([]model.Cat, error) {
-	rows, err := r.db.Query(`
-        SELECT id, name, years_of_experience, breed, salary, created_at
+func (r *CatPgRepository) GetAll(ctx context.Context) ([]model.Cat, error) {
+	return r.list(ctx, `
+        SELECT id, name, years_of_experience, breed, salary, currency, version, created_at
         FROM spy_cats
-    `)
+        WHERE tenant_id = $1
+    `, tenantID(ctx))
+}
+
+func (r *CatPgRepository) GetAvailable(ctx context.Context) ([]model.Cat, error) {
+	return r.list(ctx, `
+        SELECT c.id, c.name, c.years_of_experience, c.breed, c.salary, c.currency, c.version, c.created_at
+        FROM spy_cats c
+        WHERE c.tenant_id = $1 AND NOT EXISTS (
+            SELECT 1 FROM missions m WHERE m.cat_id = c.id AND m.completed = false
+        )
+        ORDER BY c.id
+    `, tenantID(ctx))
+}
+
+func (r *CatPgRepository) list(ctx context.Context, query string, args ...any) ([]model.Cat, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +79,7 @@ func (r *CatPgRepository) GetAll() ([]model.Cat, error) {
 	var cats []model.Cat
 	for rows.Next() {
 		var c model.Cat
-		if err := rows.Scan(&c.ID, &c.Name, &c.YearsOfExperience, &c.Breed, &c.Salary, &c.CreatedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.Name, &c.YearsOfExperience, &c.Breed, &c.Salary.Amount, &c.Salary.Currency, &c.Version, &c.CreatedAt); err != nil {
 			return nil, err
 		}
 		cats = append(cats, c)
@@ -64,18 +87,25 @@ func (r *CatPgRepository) GetAll() ([]model.Cat, error) {
 	return cats, nil
 }
 
-func (r *CatPgRepository) Update(cat *model.Cat) error {
+// Update saves the cat if its version is still cat.Version and stores the new version in cat.
+func (r *CatPgRepository) Update(ctx context.Context, cat *model.Cat) error {
 	query := `
         UPDATE spy_cats
-        SET name = $1, years_of_experience = $2, breed = $3, salary = $4
-        WHERE id = $5
+        SET name = $1, years_of_experience = $2, breed = $3, salary = $4, currency = $5, version = version + 1
+        WHERE id = $6 AND version = $7 AND tenant_id = $8
+        RETURNING version
     `
-	_, err := r.db.Exec(query, cat.Name, cat.YearsOfExperience, cat.Breed, cat.Salary, cat.ID)
+	err := r.conn(ctx).QueryRowContext(ctx, query, cat.Name, cat.YearsOfExperience, cat.Breed,
+		cat.Salary.Amount, cat.Salary.Currency, cat.ID, cat.Version, tenantID(ctx)).
+		Scan(&cat.Version)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("cat %d version %d: %w", cat.ID, cat.Version, domain.ErrVersionConflict)
+	}
 	return err
 }
 
-func (r *CatPgRepository) Delete(id int) error {
-	res, err := r.db.Exec(`DELETE FROM spy_cats WHERE id=$1`, id)
+func (r *CatPgRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM spy_cats WHERE id=$1 AND tenant_id=$2`, id, tenantID(ctx))
 	if err != nil {
 		return err
 	}
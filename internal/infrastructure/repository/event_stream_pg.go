@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
+)
+
+type EventStreamPgRepository struct {
+	db *sql.DB
+}
+
+func NewEventStreamPgRepository(db *sql.DB) domain.EventStreamRepository {
+	return &EventStreamPgRepository{db: db}
+}
+
+func (r *EventStreamPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+// streamEventQuery resolves the mission and the cat of every event: target events
+// carry the mission in the payload, and its cat is looked up. Events of all agencies
+// are read (see list), the broker filters them per subscriber.
+const streamEventQuery = `
+        SELECT e.id, e.event_type, e.aggregate_type, e.aggregate_id, e.payload, e.actor,
+               COALESCE(e.request_id, ''), e.occurred_at, e.tenant_id,
+               CASE e.aggregate_type
+                   WHEN 'mission' THEN e.aggregate_id
+                   WHEN 'target' THEN (e.payload->>'mission_id')::int
+               END AS mission_id,
+               CASE e.aggregate_type
+                   WHEN 'cat' THEN e.aggregate_id
+                   WHEN 'mission' THEN (e.payload->>'cat_id')::int
+                   WHEN 'target' THEN (SELECT m.cat_id FROM missions m
+                                       WHERE m.id = (e.payload->>'mission_id')::int AND m.tenant_id = e.tenant_id)
+               END AS cat_id
+        FROM outbox_events e
+`
+
+func (r *EventStreamPgRepository) GetByID(ctx context.Context, id int64) (*model.StreamEvent, error) {
+	events, err := r.list(ctx, streamEventQuery+` WHERE e.id = $1`, id)
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+	return &events[0], nil
+}
+
+func (r *EventStreamPgRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]model.StreamEvent, error) {
+	return r.list(ctx, streamEventQuery+` WHERE e.id > $1 ORDER BY e.id LIMIT $2`, afterID, limit)
+}
+
+func (r *EventStreamPgRepository) ListLatest(ctx context.Context, limit int) ([]model.StreamEvent, error) {
+	return r.list(ctx, `SELECT * FROM (`+streamEventQuery+` ORDER BY e.id DESC LIMIT $1) latest ORDER BY id`, limit)
+}
+
+// list reads across agencies, the cats of target events are looked up in the missions
+// of every agency.
+func (r *EventStreamPgRepository) list(ctx context.Context, query string, args ...any) ([]model.StreamEvent, error) {
+	var events []model.StreamEvent
+	err := pg.AcrossTenants(ctx, r.db, func(ctx context.Context) error {
+		rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var e model.StreamEvent
+			if err := rows.Scan(&e.ID, &e.Type, &e.AggregateType, &e.AggregateID, &e.Payload, &e.Actor,
+				&e.RequestID, &e.OccurredAt, &e.TenantID, &e.MissionID, &e.CatID); err != nil {
+				return err
+			}
+			events = append(events, e)
+		}
+		return rows.Err()
+	})
+	return events, err
+}
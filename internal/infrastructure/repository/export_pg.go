@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
+)
+
+// exportFetchSize is the number of rows fetched from the server-side cursor at once.
+const exportFetchSize = 500
+
+type ExportPgRepository struct {
+	db *sql.DB
+}
+
+func NewExportPgRepository(db *sql.DB) domain.ExportRepository {
+	return &ExportPgRepository{db: db}
+}
+
+func (r *ExportPgRepository) StreamCats(ctx context.Context, f model.ExportFilter, fn func(model.Cat) error) error {
+	query := `
+        SELECT id, name, years_of_experience, breed, salary, currency, version, created_at
+        FROM spy_cats
+    ` + exportWhere("tenant_id", "updated_at", f) + ` ORDER BY id`
+
+	return r.stream(ctx, query, exportArgs(ctx, f), func(rows *sql.Rows) error {
+		var c model.Cat
+		if err := rows.Scan(&c.ID, &c.Name, &c.YearsOfExperience, &c.Breed, &c.Salary.Amount, &c.Salary.Currency, &c.Version, &c.CreatedAt); err != nil {
+			return err
+		}
+		return fn(c)
+	})
+}
+
+// missionModifiedAt is the last modification of a mission or of one of its targets.
+// GREATEST ignores the NULL of missions without targets.
+const missionModifiedAt = `GREATEST(m.updated_at, (SELECT MAX(tu.updated_at) FROM targets tu WHERE tu.mission_id = m.id))`
+
+func (r *ExportPgRepository) StreamMissions(ctx context.Context, f model.ExportFilter, fn func(model.Mission) error) error {
+	// Targets are aggregated into JSON so every mission is a single cursor row.
+	query := `
+        SELECT m.id, m.cat_id, m.completed, m.completed_at, m.version, m.created_at,
+               COALESCE(
+                   json_agg(json_build_object(
+                       'id', t.id,
+                       'mission_id', t.mission_id,
+                       'name', t.name,
+                       'country', t.country,
+                       'notes', t.notes,
+                       'complete', t.complete,
+                       'version', t.version,
+                       'created_at', t.created_at
+                   ) ORDER BY t.id) FILTER (WHERE t.id IS NOT NULL),
+                   '[]'
+               )
+        FROM missions m
+        LEFT JOIN targets t ON t.mission_id = m.id
+    ` + exportWhere("m.tenant_id", missionModifiedAt, f) + `
+        GROUP BY m.id
+        ORDER BY m.id
+    `
+
+	return r.stream(ctx, query, exportArgs(ctx, f), func(rows *sql.Rows) error {
+		var (
+			m       model.Mission
+			targets []byte
+		)
+		if err := rows.Scan(&m.ID, &m.CatID, &m.Completed, &m.CompletedAt, &m.Version, &m.CreatedAt, &targets); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(targets, &m.Targets); err != nil {
+			return fmt.Errorf("failed to decode targets of mission %d: %w", m.ID, err)
+		}
+		return fn(m)
+	})
+}
+
+func (r *ExportPgRepository) StreamAuditEvents(ctx context.Context, f model.ExportFilter, fn func(model.AuditEvent) error) error {
+	query := `
+        SELECT id, actor, action, entity_type, entity_id, before, after, COALESCE(request_id, ''), created_at
+        FROM audit_events
+    ` + exportWhere("tenant_id", "created_at", f) + ` ORDER BY id`
+
+	return r.stream(ctx, query, exportArgs(ctx, f), func(rows *sql.Rows) error {
+		var (
+			e             model.AuditEvent
+			before, after []byte
+		)
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Action, &e.EntityType, &e.EntityID,
+			&before, &after, &e.RequestID, &e.CreatedAt); err != nil {
+			return err
+		}
+		e.Before, e.After = before, after
+		return fn(e)
+	})
+}
+
+// stream reads query through a server-side cursor in a read-only transaction of the request,
+// fetching exportFetchSize rows at a time and calling scan for each of them.
+func (r *ExportPgRepository) stream(ctx context.Context, query string, args []any, scan func(*sql.Rows) error) error {
+	const op = "repository.export.stream"
+
+	tx, err := pg.BeginTx(ctx, r.db, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DECLARE export_cursor NO SCROLL CURSOR FOR `+query, args...); err != nil {
+		return fmt.Errorf("%s: declare cursor: %w", op, err)
+	}
+
+	fetch := fmt.Sprintf(`FETCH FORWARD %d FROM export_cursor`, exportFetchSize)
+	for {
+		rows, err := tx.QueryContext(ctx, fetch)
+		if err != nil {
+			return fmt.Errorf("%s: fetch: %w", op, err)
+		}
+		n := 0
+		for rows.Next() {
+			n++
+			if err := scan(rows); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("%s: fetch: %w", op, err)
+		}
+		if n < exportFetchSize {
+			break
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `CLOSE export_cursor`); err != nil {
+		return fmt.Errorf("%s: close cursor: %w", op, err)
+	}
+	return tx.Commit()
+}
+
+// exportWhere scopes an export to the agency ($1) and the time range of the filter,
+// applied to the modification time in column.
+func exportWhere(tenantColumn, column string, f model.ExportFilter) string {
+	conds := []string{tenantColumn + " = $1"}
+	n := 1
+	if !f.Since.IsZero() {
+		n++
+		conds = append(conds, fmt.Sprintf("%s >= $%d", column, n))
+	}
+	if !f.Until.IsZero() {
+		n++
+		conds = append(conds, fmt.Sprintf("%s < $%d", column, n))
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
+
+func exportArgs(ctx context.Context, f model.ExportFilter) []any {
+	args := []any{tenantID(ctx)}
+	if !f.Since.IsZero() {
+		args = append(args, f.Since)
+	}
+	if !f.Until.IsZero() {
+		args = append(args, f.Until)
+	}
+	return args
+}
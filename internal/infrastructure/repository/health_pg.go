@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+)
+
+type HealthPgRepository struct {
+	db *sql.DB
+}
+
+func NewHealthPgRepository(db *sql.DB) domain.HealthRepository {
+	return &HealthPgRepository{db: db}
+}
+
+func (r *HealthPgRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// SchemaVersion reads the schema_migrations table of golang-migrate.
+func (r *HealthPgRepository) SchemaVersion(ctx context.Context) (int, bool, error) {
+	var (
+		version int
+		dirty   bool
+	)
+	err := r.db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
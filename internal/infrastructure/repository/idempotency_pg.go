@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
+)
+
+type IdempotencyPgRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyPgRepository(db *sql.DB) domain.IdempotencyRepository {
+	return &IdempotencyPgRepository{db: db}
+}
+
+func (r *IdempotencyPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+func (r *IdempotencyPgRepository) Reserve(ctx context.Context, rec *model.IdempotencyRecord) (bool, error) {
+	// The conditional DO UPDATE takes over an expired key, or the key of the same request left
+	// uncompleted past its lock, and returns no row for a live one.
+	query := `
+        INSERT INTO idempotency_keys (tenant_id, actor, key, fingerprint, locked_until, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (tenant_id, actor, key) DO UPDATE
+        SET fingerprint = EXCLUDED.fingerprint,
+            status_code = NULL,
+            content_type = NULL,
+            response_headers = NULL,
+            response_body = NULL,
+            created_at = now(),
+            locked_until = EXCLUDED.locked_until,
+            expires_at = EXCLUDED.expires_at
+        WHERE idempotency_keys.expires_at <= now()
+           OR (idempotency_keys.status_code IS NULL
+               AND idempotency_keys.locked_until <= now()
+               AND idempotency_keys.fingerprint = EXCLUDED.fingerprint)
+        RETURNING created_at
+    `
+	err := r.conn(ctx).QueryRowContext(ctx, query, tenantID(ctx), rec.Actor, rec.Key, rec.Fingerprint, rec.LockedUntil, rec.ExpiresAt).
+		Scan(&rec.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *IdempotencyPgRepository) Get(ctx context.Context, actor, key string) (*model.IdempotencyRecord, error) {
+	query := `
+        SELECT actor, key, fingerprint, COALESCE(status_code, 0), COALESCE(content_type, ''),
+               response_headers, response_body, created_at, locked_until, expires_at
+        FROM idempotency_keys
+        WHERE tenant_id = $1 AND actor = $2 AND key = $3
+    `
+	var (
+		rec     model.IdempotencyRecord
+		headers []byte
+	)
+	err := r.conn(ctx).QueryRowContext(ctx, query, tenantID(ctx), actor, key).Scan(
+		&rec.Actor, &rec.Key, &rec.Fingerprint, &rec.StatusCode, &rec.ContentType,
+		&headers, &rec.Body, &rec.CreatedAt, &rec.LockedUntil, &rec.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if headers != nil {
+		if err := json.Unmarshal(headers, &rec.Headers); err != nil {
+			return nil, fmt.Errorf("failed to decode response headers of idempotency key %q: %w", key, err)
+		}
+	}
+	return &rec, nil
+}
+
+func (r *IdempotencyPgRepository) Complete(ctx context.Context, rec *model.IdempotencyRecord) error {
+	headers, err := json.Marshal(rec.Headers)
+	if err != nil {
+		return err
+	}
+	query := `
+        UPDATE idempotency_keys
+        SET status_code = $1, content_type = $2, response_headers = $3, response_body = $4
+        WHERE tenant_id = $5 AND actor = $6 AND key = $7 AND created_at = $8
+    `
+	_, err = r.conn(ctx).ExecContext(ctx, query, rec.StatusCode, rec.ContentType, headers, rec.Body,
+		tenantID(ctx), rec.Actor, rec.Key, rec.CreatedAt)
+	return err
+}
+
+func (r *IdempotencyPgRepository) Delete(ctx context.Context, rec *model.IdempotencyRecord) error {
+	_, err := r.conn(ctx).ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE tenant_id = $1 AND actor = $2 AND key = $3 AND created_at = $4`,
+		tenantID(ctx), rec.Actor, rec.Key, rec.CreatedAt)
+	return err
+}
+
+func (r *IdempotencyPgRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	res, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= now()`)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
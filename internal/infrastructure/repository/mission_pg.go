@@ -1,10 +1,13 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/alextotalk/feline-intelligence/internal/domain"
 	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
 )
 
 type MissionPgRepository struct {
@@ -15,25 +18,29 @@ func NewMissionPgRepository(db *sql.DB) domain.MissionRepository {
 	return &MissionPgRepository{db: db}
 }
 
-func (r *MissionPgRepository) Create(m *model.Mission) error {
+func (r *MissionPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+func (r *MissionPgRepository) Create(ctx context.Context, m *model.Mission) error {
 	query := `
-        INSERT INTO missions (cat_id, completed)
-        VALUES ($1, $2)
-        RETURNING id, created_at
+        INSERT INTO missions (cat_id, completed, tenant_id)
+        VALUES ($1, $2, $3)
+        RETURNING id, version, created_at
     `
-	return r.db.QueryRow(query, m.CatID, m.Completed).
-		Scan(&m.ID, &m.CreatedAt)
+	return r.conn(ctx).QueryRowContext(ctx, query, m.CatID, m.Completed, tenantID(ctx)).
+		Scan(&m.ID, &m.Version, &m.CreatedAt)
 }
 
-func (r *MissionPgRepository) GetByID(id int) (*model.Mission, error) {
+func (r *MissionPgRepository) GetByID(ctx context.Context, id int) (*model.Mission, error) {
 	var ms model.Mission
 	query := `
-        SELECT id, cat_id, completed, created_at
+        SELECT id, cat_id, completed, completed_at, version, created_at
         FROM missions
-        WHERE id = $1
+        WHERE id = $1 AND tenant_id = $2
     `
-	err := r.db.QueryRow(query, id).
-		Scan(&ms.ID, &ms.CatID, &ms.Completed, &ms.CreatedAt)
+	err := r.conn(ctx).QueryRowContext(ctx, query, id, tenantID(ctx)).
+		Scan(&ms.ID, &ms.CatID, &ms.Completed, &ms.CompletedAt, &ms.Version, &ms.CreatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	} else if err != nil {
@@ -42,11 +49,11 @@ func (r *MissionPgRepository) GetByID(id int) (*model.Mission, error) {
 
 	// Витягуємо Targets для цієї місії
 	tQuery := `
-        SELECT id, mission_id, name, country, notes, complete, created_at
+        SELECT id, mission_id, name, country, notes, complete, version, created_at
         FROM targets
         WHERE mission_id = $1
     `
-	rows, err := r.db.Query(tQuery, ms.ID)
+	rows, err := r.conn(ctx).QueryContext(ctx, tQuery, ms.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +62,7 @@ func (r *MissionPgRepository) GetByID(id int) (*model.Mission, error) {
 	var targets []model.Target
 	for rows.Next() {
 		var t model.Target
-		if err := rows.Scan(&t.ID, &t.MissionID, &t.Name, &t.Country, &t.Notes, &t.Complete, &t.CreatedAt); err != nil {
+		if err := rows.Scan(&t.ID, &t.MissionID, &t.Name, &t.Country, &t.Notes, &t.Complete, &t.Version, &t.CreatedAt); err != nil {
 			return nil, err
 		}
 		targets = append(targets, t)
@@ -65,12 +72,13 @@ func (r *MissionPgRepository) GetByID(id int) (*model.Mission, error) {
 	return &ms, nil
 }
 
-func (r *MissionPgRepository) GetAll() ([]model.Mission, error) {
-	rows, err := r.db.Query(`
-        SELECT id, cat_id, completed, created_at
+func (r *MissionPgRepository) GetAll(ctx context.Context) ([]model.Mission, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+        SELECT id, cat_id, completed, completed_at, version, created_at
         FROM missions
+        WHERE tenant_id = $1
         ORDER BY id
-    `)
+    `, tenantID(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -79,22 +87,31 @@ func (r *MissionPgRepository) GetAll() ([]model.Mission, error) {
 	var missions []model.Mission
 	for rows.Next() {
 		var ms model.Mission
-		if err := rows.Scan(&ms.ID, &ms.CatID, &ms.Completed, &ms.CreatedAt); err != nil {
+		if err := rows.Scan(&ms.ID, &ms.CatID, &ms.Completed, &ms.CompletedAt, &ms.Version, &ms.CreatedAt); err != nil {
 			return nil, err
 		}
-
-		// Витягуємо Targets
-		tRows, err := r.db.Query(`
-            SELECT id, mission_id, name, country, notes, complete, created_at
+		missions = append(missions, ms)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// The targets are read after the missions, a connection cannot run a query while
+	// the rows of another one are open (see pg.Conn).
+	rows.Close()
+
+	// Витягуємо Targets
+	for i := range missions {
+		tRows, err := r.conn(ctx).QueryContext(ctx, `
+            SELECT id, mission_id, name, country, notes, complete, version, created_at
             FROM targets WHERE mission_id = $1
-        `, ms.ID)
+        `, missions[i].ID)
 		if err != nil {
 			return nil, err
 		}
 		var targets []model.Target
 		for tRows.Next() {
 			var t model.Target
-			if err := tRows.Scan(&t.ID, &t.MissionID, &t.Name, &t.Country, &t.Notes, &t.Complete, &t.CreatedAt); err != nil {
+			if err := tRows.Scan(&t.ID, &t.MissionID, &t.Name, &t.Country, &t.Notes, &t.Complete, &t.Version, &t.CreatedAt); err != nil {
 				tRows.Close()
 				return nil, err
 			}
@@ -102,30 +119,42 @@ func (r *MissionPgRepository) GetAll() ([]model.Mission, error) {
 		}
 		tRows.Close()
 
-		ms.Targets = targets
-		missions = append(missions, ms)
+		missions[i].Targets = targets
 	}
 
 	return missions, nil
 }
 
-func (r *MissionPgRepository) Update(m *model.Mission) error {
+func (r *MissionPgRepository) GetActiveByCat(ctx context.Context, catID int) (*model.Mission, error) {
+	var id int
+	// A cat has at most one active mission (idx_unique_active_mission).
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`SELECT id FROM missions WHERE cat_id = $1 AND completed = false AND tenant_id = $2`, catID, tenantID(ctx)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Update saves the mission if its version is still m.Version and stores the new version in m.
+func (r *MissionPgRepository) Update(ctx context.Context, m *model.Mission) error {
 	query := `
         UPDATE missions
-        SET cat_id = $1, completed = $2
-        WHERE id = $3
+        SET cat_id = $1, completed = $2, version = version + 1
+        WHERE id = $3 AND version = $4 AND tenant_id = $5
+        RETURNING version, completed_at
     `
-	_, err := r.db.Exec(query, m.CatID, m.Completed, m.ID)
-	return err
-}
-
-func (r *MissionPgRepository) Delete(id int) error {
-	_, err := r.db.Exec(`DELETE FROM missions WHERE id=$1`, id)
+	err := r.conn(ctx).QueryRowContext(ctx, query, m.CatID, m.Completed, m.ID, m.Version, tenantID(ctx)).
+		Scan(&m.Version, &m.CompletedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("mission %d version %d: %w", m.ID, m.Version, domain.ErrVersionConflict)
+	}
 	return err
 }
 
-func (r *MissionPgRepository) AssignCat(missionID, catID int) error {
-	// Записуємо в поле cat_id
-	_, err := r.db.Exec(`UPDATE missions SET cat_id=$1 WHERE id=$2`, catID, missionID)
+func (r *MissionPgRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM missions WHERE id=$1 AND tenant_id=$2`, id, tenantID(ctx))
 	return err
 }
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
+)
+
+type OutboxPgRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxPgRepository(db *sql.DB) domain.OutboxRepository {
+	return &OutboxPgRepository{db: db}
+}
+
+func (r *OutboxPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+func (r *OutboxPgRepository) Add(ctx context.Context, e *model.DomainEvent) error {
+	query := `
+        INSERT INTO outbox_events (event_type, aggregate_type, aggregate_id, payload, actor, request_id, tenant_id)
+        VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+        RETURNING id, occurred_at, tenant_id
+    `
+	return r.conn(ctx).QueryRowContext(ctx, query, e.Type, e.AggregateType, e.AggregateID,
+		[]byte(e.Payload), e.Actor, e.RequestID, tenantID(ctx)).
+		Scan(&e.ID, &e.OccurredAt, &e.TenantID)
+}
+
+func (r *OutboxPgRepository) ClaimPending(ctx context.Context, limit int) ([]model.DomainEvent, error) {
+	query := `
+        SELECT id, event_type, aggregate_type, aggregate_id, payload, actor, COALESCE(request_id, ''), occurred_at,
+               tenant_id, attempts
+        FROM outbox_events
+        WHERE dispatched_at IS NULL AND next_attempt_at <= now()
+        ORDER BY id
+        LIMIT $1
+        FOR UPDATE SKIP LOCKED
+    `
+	rows, err := r.conn(ctx).QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []model.DomainEvent
+	for rows.Next() {
+		var e model.DomainEvent
+		if err := rows.Scan(&e.ID, &e.Type, &e.AggregateType, &e.AggregateID, &e.Payload,
+			&e.Actor, &e.RequestID, &e.OccurredAt, &e.TenantID, &e.Attempts); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (r *OutboxPgRepository) MarkDispatched(ctx context.Context, id int64) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `UPDATE outbox_events SET dispatched_at = now() WHERE id = $1`, id)
+	return err
+}
+
+func (r *OutboxPgRepository) MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	query := `
+        UPDATE outbox_events
+        SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2
+        WHERE id = $3
+    `
+	_, err := r.conn(ctx).ExecContext(ctx, query, reason, nextAttemptAt, id)
+	return err
+}
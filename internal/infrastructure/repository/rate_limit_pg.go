@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+)
+
+// rateLimitSweepInterval is how often buckets that filled up again are deleted.
+const rateLimitSweepInterval = time.Minute
+
+// takeTokenQuery refills the bucket $1 at $3 tokens per second up to $2 and takes a token
+// when one is available. The existing bucket is locked, so concurrent requests are counted
+// one after another; a missing bucket starts full.
+const takeTokenQuery = `
+    WITH current AS (
+        SELECT LEAST($2::float8, COALESCE(
+            (SELECT tokens + EXTRACT(EPOCH FROM now() - updated_at) * $3::float8
+             FROM rate_limit_buckets WHERE key = $1 FOR UPDATE),
+            $2::float8)) AS tokens
+    ), taken AS (
+        SELECT tokens >= 1 AS allowed,
+               CASE WHEN tokens >= 1 THEN tokens - 1 ELSE tokens END AS tokens
+        FROM current
+    ), saved AS (
+        INSERT INTO rate_limit_buckets AS b (key, tokens, updated_at, full_at)
+        SELECT $1, tokens, now(), now() + make_interval(secs => ($2::float8 - tokens) / $3::float8)
+        FROM taken
+        ON CONFLICT (key) DO UPDATE
+            SET tokens = EXCLUDED.tokens, updated_at = EXCLUDED.updated_at, full_at = EXCLUDED.full_at
+    )
+    SELECT allowed, tokens FROM taken
+`
+
+// RateLimitPgRepository keeps token buckets in Postgres, so limits hold across replicas.
+type RateLimitPgRepository struct {
+	db *sql.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewRateLimitPgRepository(db *sql.DB) domain.RateLimitStore {
+	return &RateLimitPgRepository{db: db, lastSweep: time.Now()}
+}
+
+func (r *RateLimitPgRepository) Take(ctx context.Context, key string, limit model.RateLimit) (bool, float64, error) {
+	if err := r.sweep(ctx); err != nil {
+		return false, 0, err
+	}
+	var (
+		allowed bool
+		tokens  float64
+	)
+	err := r.db.QueryRowContext(ctx, takeTokenQuery, key, limit.Burst, limit.Rate()).Scan(&allowed, &tokens)
+	return allowed, tokens, err
+}
+
+// sweep deletes the buckets that filled up again, at most once per rateLimitSweepInterval.
+func (r *RateLimitPgRepository) sweep(ctx context.Context) error {
+	r.mu.Lock()
+	due := time.Since(r.lastSweep) >= rateLimitSweepInterval
+	if due {
+		r.lastSweep = time.Now()
+	}
+	r.mu.Unlock()
+	if !due {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE full_at <= now()`)
+	return err
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
+)
+
+type ReportPgRepository struct {
+	db *sql.DB
+}
+
+func NewReportPgRepository(db *sql.DB) domain.ReportRepository {
+	return &ReportPgRepository{db: db}
+}
+
+func (r *ReportPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+func (r *ReportPgRepository) PayrollEntries(ctx context.Context, asOf *time.Time) ([]model.PayrollEntry, error) {
+	query := `
+        SELECT id, breed, years_of_experience, salary, currency
+        FROM spy_cats
+        WHERE tenant_id = $1
+        ORDER BY id
+    `
+	args := []any{tenantID(ctx)}
+	if asOf != nil {
+		// The salary on the cutoff date is the latest applied change effective by then.
+		// If every applied change is later, the cat still had the previous salary of the first one.
+		query = `
+            SELECT c.id, c.breed, c.years_of_experience,
+                   COALESCE(
+                       (SELECT h.new_salary FROM salary_history h
+                        WHERE h.cat_id = c.id AND h.tenant_id = c.tenant_id AND h.status = 'applied' AND h.effective_date <= $2
+                        ORDER BY h.effective_date DESC, h.id DESC LIMIT 1),
+                       (SELECT h.previous_salary FROM salary_history h
+                        WHERE h.cat_id = c.id AND h.tenant_id = c.tenant_id AND h.status = 'applied'
+                        ORDER BY h.effective_date, h.id LIMIT 1),
+                       c.salary
+                   ),
+                   c.currency
+            FROM spy_cats c
+            WHERE c.tenant_id = $1 AND c.created_at::date <= $2
+            ORDER BY c.id
+        `
+		args = append(args, *asOf)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.PayrollEntry
+	for rows.Next() {
+		var e model.PayrollEntry
+		if err := rows.Scan(&e.CatID, &e.Breed, &e.YearsOfExperience, &e.Salary.Amount, &e.Salary.Currency); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
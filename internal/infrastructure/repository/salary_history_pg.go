@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
+)
+
+const salaryChangeColumns = `
+        id, cat_id, previous_salary, new_salary, currency, reason, status,
+        effective_date, requested_by, decided_by, decided_at, created_at
+`
+
+type SalaryHistoryPgRepository struct {
+	db *sql.DB
+}
+
+func NewSalaryHistoryPgRepository(db *sql.DB) domain.SalaryHistoryRepository {
+	return &SalaryHistoryPgRepository{db: db}
+}
+
+func (r *SalaryHistoryPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+func (r *SalaryHistoryPgRepository) Create(ctx context.Context, sc *model.SalaryChange) error {
+	query := `
+        INSERT INTO salary_history (cat_id, previous_salary, new_salary, currency, reason, status, effective_date, requested_by, tenant_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        RETURNING id, created_at
+    `
+	return r.conn(ctx).QueryRowContext(ctx, query, sc.CatID, sc.PreviousSalary.Amount, sc.NewSalary.Amount,
+		sc.NewSalary.Currency, sc.Reason,
+		sc.Status, sc.EffectiveDate, sc.RequestedBy, tenantID(ctx)).
+		Scan(&sc.ID, &sc.CreatedAt)
+}
+
+func (r *SalaryHistoryPgRepository) GetByID(ctx context.Context, id int) (*model.SalaryChange, error) {
+	query := `SELECT ` + salaryChangeColumns + ` FROM salary_history WHERE id = $1 AND tenant_id = $2`
+	return scanSalaryChange(r.conn(ctx).QueryRowContext(ctx, query, id, tenantID(ctx)))
+}
+
+func (r *SalaryHistoryPgRepository) GetPendingByCat(ctx context.Context, catID int) (*model.SalaryChange, error) {
+	query := `SELECT ` + salaryChangeColumns + ` FROM salary_history WHERE cat_id = $1 AND status = 'pending' AND tenant_id = $2`
+	return scanSalaryChange(r.conn(ctx).QueryRowContext(ctx, query, catID, tenantID(ctx)))
+}
+
+func (r *SalaryHistoryPgRepository) ListByCat(ctx context.Context, catID int) ([]model.SalaryChange, error) {
+	query := `SELECT ` + salaryChangeColumns + `
+        FROM salary_history
+        WHERE cat_id = $1 AND tenant_id = $2
+        ORDER BY effective_date DESC, id DESC
+    `
+	rows, err := r.conn(ctx).QueryContext(ctx, query, catID, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []model.SalaryChange
+	for rows.Next() {
+		sc, err := scanSalaryChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *sc)
+	}
+	return changes, rows.Err()
+}
+
+func (r *SalaryHistoryPgRepository) UpdateStatus(ctx context.Context, sc *model.SalaryChange) error {
+	query := `
+        UPDATE salary_history
+        SET status = $1, effective_date = $2, decided_by = $3, decided_at = $4
+        WHERE id = $5 AND tenant_id = $6
+    `
+	_, err := r.conn(ctx).ExecContext(ctx, query, sc.Status, sc.EffectiveDate, sc.DecidedBy, sc.DecidedAt, sc.ID, tenantID(ctx))
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSalaryChange(row rowScanner) (*model.SalaryChange, error) {
+	var sc model.SalaryChange
+	err := row.Scan(&sc.ID, &sc.CatID, &sc.PreviousSalary.Amount, &sc.NewSalary.Amount, &sc.NewSalary.Currency, &sc.Reason, &sc.Status,
+		&sc.EffectiveDate, &sc.RequestedBy, &sc.DecidedBy, &sc.DecidedAt, &sc.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	sc.PreviousSalary.Currency = sc.NewSalary.Currency
+	return &sc, nil
+}
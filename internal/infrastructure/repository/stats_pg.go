@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
+)
+
+// creditedTargetsQuery lists the completed targets of the agency $1 with the cat credited for them:
+// the cat assigned to the mission when the target was completed, or the last assigned cat for
+// targets completed before completion times were recorded.
+const creditedTargetsQuery = `
+    SELECT COALESCE(a.cat_id, m.cat_id) AS cat_id, t.country
+    FROM targets t
+    JOIN missions m ON m.id = t.mission_id
+    LEFT JOIN LATERAL (
+        SELECT ma.cat_id
+        FROM mission_assignments ma
+        WHERE ma.mission_id = t.mission_id AND ma.tenant_id = m.tenant_id
+          AND (t.completed_at IS NULL
+               OR (ma.assigned_at <= t.completed_at
+                   AND (ma.unassigned_at IS NULL OR ma.unassigned_at >= t.completed_at)))
+        ORDER BY ma.assigned_at DESC, ma.id DESC
+        LIMIT 1
+    ) a ON true
+    WHERE t.complete AND m.tenant_id = $1
+`
+
+// catStatsQuery aggregates missions and credited targets per cat of the agency $1.
+const catStatsQuery = `
+    WITH mission_stats AS (
+        SELECT cat_id,
+               COUNT(*) FILTER (WHERE completed) AS completed_missions,
+               AVG(EXTRACT(EPOCH FROM completed_at - created_at))
+                   FILTER (WHERE completed AND completed_at IS NOT NULL) AS avg_seconds,
+               MIN(id) FILTER (WHERE NOT completed) AS active_mission_id
+        FROM missions
+        WHERE cat_id IS NOT NULL AND tenant_id = $1
+        GROUP BY cat_id
+    ), target_stats AS (
+        SELECT cat_id,
+               COUNT(*) AS targets_completed,
+               ARRAY_AGG(DISTINCT country) AS countries
+        FROM (` + creditedTargetsQuery + `) ct
+        WHERE cat_id IS NOT NULL
+        GROUP BY cat_id
+    )
+    SELECT c.id, c.name,
+           COALESCE(ms.completed_missions, 0) AS completed_missions,
+           COALESCE(ts.targets_completed, 0) AS targets_completed,
+           ms.avg_seconds,
+           ms.active_mission_id,
+           COALESCE(ts.countries, '{}') AS countries
+    FROM spy_cats c
+    LEFT JOIN mission_stats ms ON ms.cat_id = c.id
+    LEFT JOIN target_stats ts ON ts.cat_id = c.id
+    WHERE c.tenant_id = $1
+`
+
+// leaderboardOrder maps sort keys to ORDER BY clauses, best first.
+var leaderboardOrder = map[string]string{
+	model.StatsSortCompletedMissions: "completed_missions DESC",
+	model.StatsSortTargetsCompleted:  "targets_completed DESC",
+	model.StatsSortAvgTimeToComplete: "avg_seconds ASC NULLS LAST",
+	model.StatsSortCountries:         "cardinality(countries) DESC",
+}
+
+type StatsPgRepository struct {
+	db *sql.DB
+}
+
+func NewStatsPgRepository(db *sql.DB) domain.StatsRepository {
+	return &StatsPgRepository{db: db}
+}
+
+func (r *StatsPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+func (r *StatsPgRepository) CatStats(ctx context.Context, catID int) (*model.CatStats, error) {
+	query := catStatsQuery + ` AND c.id = $2`
+	s, err := scanCatStats(r.conn(ctx).QueryRowContext(ctx, query, tenantID(ctx), catID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *StatsPgRepository) Leaderboard(ctx context.Context, sortBy string, limit int) ([]model.CatStats, error) {
+	order, ok := leaderboardOrder[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown leaderboard sort key %q", sortBy)
+	}
+	query := `SELECT * FROM (` + catStatsQuery + `) s ORDER BY ` + order + `, id LIMIT $2`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, tenantID(ctx), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []model.CatStats
+	for rows.Next() {
+		s, err := scanCatStats(rows)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, *s)
+	}
+	return stats, rows.Err()
+}
+
+func (r *StatsPgRepository) CompletedTargetsIn(ctx context.Context, countries []string) (map[int]int, error) {
+	query := `
+        SELECT cat_id, COUNT(*)
+        FROM (` + creditedTargetsQuery + `) ct
+        WHERE cat_id IS NOT NULL AND country = ANY($2)
+        GROUP BY cat_id
+    `
+	rows, err := r.conn(ctx).QueryContext(ctx, query, tenantID(ctx), pq.Array(countries))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var catID, count int
+		if err := rows.Scan(&catID, &count); err != nil {
+			return nil, err
+		}
+		counts[catID] = count
+	}
+	return counts, rows.Err()
+}
+
+func scanCatStats(row rowScanner) (*model.CatStats, error) {
+	var s model.CatStats
+	err := row.Scan(&s.CatID, &s.Name, &s.CompletedMissions, &s.TargetsCompleted,
+		&s.AvgTimeToCompleteSeconds, &s.ActiveMissionID, pq.Array(&s.Countries))
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *StatsPgRepository) AgencyGauges(ctx context.Context) ([]model.AgencyGauges, error) {
+	query := `
+        SELECT a.id,
+               (SELECT COUNT(*) FROM missions m
+                WHERE m.tenant_id = a.id AND NOT m.completed),
+               (SELECT COUNT(*) FROM missions m
+                WHERE m.tenant_id = a.id AND NOT m.completed AND m.cat_id IS NULL),
+               (SELECT COUNT(*) FROM spy_cats c
+                WHERE c.tenant_id = a.id
+                  AND NOT EXISTS (SELECT 1 FROM missions m WHERE m.cat_id = c.id AND NOT m.completed))
+        FROM agencies a
+        ORDER BY a.id
+    `
+	var gauges []model.AgencyGauges
+	err := pg.AcrossTenants(ctx, r.db, func(ctx context.Context) error {
+		rows, err := r.conn(ctx).QueryContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var g model.AgencyGauges
+			if err := rows.Scan(&g.TenantID, &g.ActiveMissions, &g.UnassignedMissions, &g.IdleCats); err != nil {
+				return err
+			}
+			gauges = append(gauges, g)
+		}
+		return rows.Err()
+	})
+	return gauges, err
+}
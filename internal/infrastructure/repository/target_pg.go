@@ -1,10 +1,13 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/alextotalk/feline-intelligence/internal/domain"
 	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
 )
 
 type TargetPgRepository struct {
@@ -15,40 +18,50 @@ func NewTargetPgRepository(db *sql.DB) domain.TargetRepository {
 	return &TargetPgRepository{db: db}
 }
 
-func (r *TargetPgRepository) AddToMission(t *model.Target) error {
+func (r *TargetPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+func (r *TargetPgRepository) AddToMission(ctx context.Context, t *model.Target) error {
 	query := `
-        INSERT INTO targets (mission_id, name, country, notes, complete)
-        VALUES ($1, $2, $3, $4, $5)
-        RETURNING id, created_at
+        INSERT INTO targets (mission_id, name, country, notes, complete, tenant_id)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, version, created_at
     `
-	return r.db.QueryRow(query, t.MissionID, t.Name, t.Country, t.Notes, t.Complete).
-		Scan(&t.ID, &t.CreatedAt)
+	return r.conn(ctx).QueryRowContext(ctx, query, t.MissionID, t.Name, t.Country, t.Notes, t.Complete, tenantID(ctx)).
+		Scan(&t.ID, &t.Version, &t.CreatedAt)
 }
 
-func (r *TargetPgRepository) Update(t *model.Target) error {
+// Update saves the target if its version is still t.Version and stores the new version in t.
+func (r *TargetPgRepository) Update(ctx context.Context, t *model.Target) error {
 	query := `
         UPDATE targets
-        SET name = $1, country = $2, notes = $3, complete = $4
-        WHERE id = $5
+        SET name = $1, country = $2, notes = $3, complete = $4, version = version + 1
+        WHERE id = $5 AND version = $6 AND tenant_id = $7
+        RETURNING version
     `
-	_, err := r.db.Exec(query, t.Name, t.Country, t.Notes, t.Complete, t.ID)
+	err := r.conn(ctx).QueryRowContext(ctx, query, t.Name, t.Country, t.Notes, t.Complete, t.ID, t.Version, tenantID(ctx)).
+		Scan(&t.Version)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("target %d version %d: %w", t.ID, t.Version, domain.ErrVersionConflict)
+	}
 	return err
 }
 
-func (r *TargetPgRepository) Delete(id int) error {
-	_, err := r.db.Exec(`DELETE FROM targets WHERE id=$1`, id)
+func (r *TargetPgRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM targets WHERE id=$1 AND tenant_id=$2`, id, tenantID(ctx))
 	return err
 }
 
-func (r *TargetPgRepository) GetByID(id int) (*model.Target, error) {
+func (r *TargetPgRepository) GetByID(ctx context.Context, id int) (*model.Target, error) {
 	query := `
-        SELECT id, mission_id, name, country, notes, complete, created_at
+        SELECT id, mission_id, name, country, notes, complete, version, created_at
         FROM targets
-        WHERE id=$1
+        WHERE id=$1 AND tenant_id=$2
     `
 	var t model.Target
-	err := r.db.QueryRow(query, id).Scan(
-		&t.ID, &t.MissionID, &t.Name, &t.Country, &t.Notes, &t.Complete, &t.CreatedAt,
+	err := r.conn(ctx).QueryRowContext(ctx, query, id, tenantID(ctx)).Scan(
+		&t.ID, &t.MissionID, &t.Name, &t.Country, &t.Notes, &t.Complete, &t.Version, &t.CreatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+)
+
+// tenantID returns the agency the queries of ctx are scoped to. Outside of requests,
+// e.g. in background jobs, it is the default agency.
+func tenantID(ctx context.Context) int {
+	if id, ok := requestctx.Tenant(ctx); ok {
+		return id
+	}
+	return model.DefaultAgencyID
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/storage/pg"
+)
+
+type WebhookPgRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookPgRepository(db *sql.DB) domain.WebhookRepository {
+	return &WebhookPgRepository{db: db}
+}
+
+func (r *WebhookPgRepository) conn(ctx context.Context) pg.DBTX {
+	return pg.Conn(ctx, r.db)
+}
+
+const webhookSubscriptionColumns = `id, tenant_id, url, secret, event_types, active, consecutive_failures, disabled_at, created_at`
+
+func scanWebhookSubscription(row rowScanner) (*model.WebhookSubscription, error) {
+	var s model.WebhookSubscription
+	err := row.Scan(&s.ID, &s.TenantID, &s.URL, &s.Secret, pq.Array(&s.EventTypes), &s.Active,
+		&s.ConsecutiveFailures, &s.DisabledAt, &s.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *WebhookPgRepository) Create(ctx context.Context, s *model.WebhookSubscription) error {
+	query := `
+        INSERT INTO webhook_subscriptions (url, secret, event_types, active, tenant_id)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, tenant_id, created_at
+    `
+	return r.conn(ctx).QueryRowContext(ctx, query, s.URL, s.Secret, pq.Array(s.EventTypes), s.Active, tenantID(ctx)).
+		Scan(&s.ID, &s.TenantID, &s.CreatedAt)
+}
+
+func (r *WebhookPgRepository) GetByID(ctx context.Context, id int) (*model.WebhookSubscription, error) {
+	s, err := scanWebhookSubscription(r.conn(ctx).QueryRowContext(ctx,
+		`SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2`, id, tenantID(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return s, err
+}
+
+func (r *WebhookPgRepository) List(ctx context.Context) ([]model.WebhookSubscription, error) {
+	return r.listSubscriptions(ctx,
+		`SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE tenant_id = $1 ORDER BY id`, tenantID(ctx))
+}
+
+func (r *WebhookPgRepository) ListSubscribed(ctx context.Context, tenantID int, eventType string) ([]model.WebhookSubscription, error) {
+	return r.listSubscriptions(ctx, `
+        SELECT `+webhookSubscriptionColumns+`
+        FROM webhook_subscriptions
+        WHERE tenant_id = $1 AND active AND (event_types = '{}' OR $2 = ANY(event_types))
+        ORDER BY id
+    `, tenantID, eventType)
+}
+
+func (r *WebhookPgRepository) listSubscriptions(ctx context.Context, query string, args ...any) ([]model.WebhookSubscription, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []model.WebhookSubscription
+	for rows.Next() {
+		s, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, *s)
+	}
+	return subs, rows.Err()
+}
+
+func (r *WebhookPgRepository) Update(ctx context.Context, s *model.WebhookSubscription) error {
+	query := `
+        UPDATE webhook_subscriptions
+        SET url = $1, event_types = $2, active = $3, consecutive_failures = $4, disabled_at = $5
+        WHERE id = $6 AND tenant_id = $7
+    `
+	_, err := r.conn(ctx).ExecContext(ctx, query, s.URL, pq.Array(s.EventTypes), s.Active,
+		s.ConsecutiveFailures, s.DisabledAt, s.ID, tenantID(ctx))
+	return err
+}
+
+func (r *WebhookPgRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2`, id, tenantID(ctx))
+	return err
+}
+
+func (r *WebhookPgRepository) RecordFailure(ctx context.Context, id int, disableAfter int) (bool, error) {
+	query := `
+        UPDATE webhook_subscriptions
+        SET consecutive_failures = consecutive_failures + 1,
+            active = active AND consecutive_failures + 1 < $1,
+            disabled_at = CASE
+                WHEN active AND consecutive_failures + 1 >= $1 THEN now()
+                ELSE disabled_at
+            END
+        WHERE id = $2
+        RETURNING active
+    `
+	var active bool
+	if err := r.conn(ctx).QueryRowContext(ctx, query, disableAfter, id).Scan(&active); err != nil {
+		return false, err
+	}
+	return !active, nil
+}
+
+func (r *WebhookPgRepository) ResetFailures(ctx context.Context, id int) error {
+	_, err := r.conn(ctx).ExecContext(ctx,
+		`UPDATE webhook_subscriptions SET consecutive_failures = 0 WHERE id = $1 AND consecutive_failures > 0`, id)
+	return err
+}
+
+func (r *WebhookPgRepository) EnqueueDelivery(ctx context.Context, d *model.WebhookDelivery) error {
+	query := `
+        INSERT INTO webhook_deliveries (subscription_id, tenant_id, event_id, event_type, payload)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (subscription_id, event_id) DO NOTHING
+    `
+	_, err := r.conn(ctx).ExecContext(ctx, query, d.SubscriptionID, d.TenantID, d.EventID, d.EventType, []byte(d.Payload))
+	return err
+}
+
+const webhookDeliveryColumns = `d.id, d.subscription_id, d.tenant_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+               d.next_attempt_at, d.last_status_code, COALESCE(d.last_error, ''), d.created_at, d.delivered_at`
+
+func (r *WebhookPgRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	return r.listDeliveries(ctx, `
+        WITH claimed AS (
+            UPDATE webhook_deliveries
+            SET next_attempt_at = now() + make_interval(secs => $2)
+            WHERE id IN (
+                SELECT d.id
+                FROM webhook_deliveries d
+                JOIN webhook_subscriptions s ON s.id = d.subscription_id
+                WHERE d.status = 'pending' AND d.next_attempt_at <= now() AND s.active
+                ORDER BY d.id
+                LIMIT $1
+                FOR UPDATE OF d SKIP LOCKED
+            )
+            RETURNING *
+        )
+        SELECT `+webhookDeliveryColumns+`
+        FROM claimed d
+        ORDER BY d.id
+    `, limit, lease.Seconds())
+}
+
+func (r *WebhookPgRepository) UpdateDelivery(ctx context.Context, d *model.WebhookDelivery, claimedUntil time.Time) (bool, error) {
+	query := `
+        UPDATE webhook_deliveries
+        SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4,
+            last_error = NULLIF($5, ''), delivered_at = $6
+        WHERE id = $7 AND next_attempt_at = $8
+    `
+	res, err := r.conn(ctx).ExecContext(ctx, query, d.Status, d.Attempts, d.NextAttemptAt, d.LastStatusCode,
+		d.LastError, d.DeliveredAt, d.ID, claimedUntil)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *WebhookPgRepository) ListDeliveries(ctx context.Context, subscriptionID, limit int) ([]model.WebhookDelivery, error) {
+	return r.listDeliveries(ctx, `
+        SELECT `+webhookDeliveryColumns+`
+        FROM webhook_deliveries d
+        WHERE d.subscription_id = $1 AND d.tenant_id = $2
+        ORDER BY d.id DESC
+        LIMIT $3
+    `, subscriptionID, tenantID(ctx), limit)
+}
+
+func (r *WebhookPgRepository) listDeliveries(ctx context.Context, query string, args ...any) ([]model.WebhookDelivery, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []model.WebhookDelivery
+	for rows.Next() {
+		var d model.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.TenantID, &d.EventID, &d.EventType, &d.Payload, &d.Status,
+			&d.Attempts, &d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
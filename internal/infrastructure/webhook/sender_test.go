@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+)
+
+func TestSendSignsPayload(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	got := make(chan received, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{header: r.Header, body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	sub := model.WebhookSubscription{ID: 1, URL: receiver.URL, Secret: "whsec_test"}
+	delivery := model.WebhookDelivery{ID: 42, EventType: model.EventMissionCompleted, Payload: []byte(`{"mission_id":7}`)}
+	status, err := NewSender(receiver.Client()).Send(context.Background(), sub, delivery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", status)
+	}
+
+	r := <-got
+	if string(r.body) != `{"mission_id":7}` {
+		t.Errorf("body = %s, want the payload", r.body)
+	}
+	if r.header.Get(HeaderEvent) != model.EventMissionCompleted || r.header.Get(HeaderDelivery) != "42" {
+		t.Errorf("event headers = %q, %q", r.header.Get(HeaderEvent), r.header.Get(HeaderDelivery))
+	}
+	timestamp, err := strconv.ParseInt(r.header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid %s: %v", HeaderTimestamp, err)
+	}
+	signature := r.header.Get(HeaderSignature)
+	if !Verify("whsec_test", timestamp, r.body, signature) {
+		t.Errorf("signature %q does not match the body", signature)
+	}
+	if Verify("other", timestamp, r.body, signature) || Verify("whsec_test", timestamp+1, r.body, signature) {
+		t.Error("signature verified with another secret or timestamp")
+	}
+	if Verify("whsec_test", timestamp, []byte(`{"mission_id":8}`), signature) {
+		t.Error("signature verified with another body")
+	}
+}
+
+func TestSendFailsOnErrorStatus(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer receiver.Close()
+
+	status, err := NewSender(receiver.Client()).Send(context.Background(),
+		model.WebhookSubscription{URL: receiver.URL}, model.WebhookDelivery{Payload: []byte(`{}`)})
+	if err == nil {
+		t.Error("delivery answered with 502 succeeded")
+	}
+	if status != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", status)
+	}
+
+	receiver.Close()
+	if status, err := NewSender(http.DefaultClient).Send(context.Background(),
+		model.WebhookSubscription{URL: receiver.URL}, model.WebhookDelivery{Payload: []byte(`{}`)}); err == nil || status != 0 {
+		t.Errorf("unreachable endpoint: status = %d, err = %v, want 0 and an error", status, err)
+	}
+}
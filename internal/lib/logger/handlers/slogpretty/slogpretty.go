@@ -45,7 +45,13 @@ func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
 		level = color.RedString(level)
 	}
 
-	fields := make(map[string]interface{}, r.NumAttrs())
+	fields := make(map[string]interface{}, len(h.attrs)+r.NumAttrs())
+
+	// Attributes of the logger, e.g. request_id and route of request loggers, come first,
+	// so the ones of the record win.
+	for _, a := range h.attrs {
+		fields[a.Key] = a.Value.Any()
+	}
 
 	r.Attrs(func(a slog.Attr) bool {
 		fields[a.Key] = a.Value.Any()
@@ -53,10 +59,6 @@ func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
 		return true
 	})
 
-	for _, a := range h.attrs {
-		fields[a.Key] = a.Value.Any()
-	}
-
 	var b []byte
 	var err error
 
@@ -67,7 +69,7 @@ func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
 		}
 	}
 
-	timeStr := r.Time.Format("[15:05:05.000]")
+	timeStr := r.Time.Format("[15:04:05.000]")
 	msg := color.CyanString(r.Message)
 
 	h.l.Println(
@@ -82,9 +84,9 @@ func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
 
 func (h *PrettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &PrettyHandler{
-		Handler: h.Handler,
+		Handler: h.Handler.WithAttrs(attrs),
 		l:       h.l,
-		attrs:   attrs,
+		attrs:   append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...),
 	}
 }
 
@@ -93,5 +95,6 @@ func (h *PrettyHandler) WithGroup(name string) slog.Handler {
 	return &PrettyHandler{
 		Handler: h.Handler.WithGroup(name),
 		l:       h.l,
+		attrs:   h.attrs,
 	}
 }
@@ -0,0 +1,93 @@
+package requestctx
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+)
+
+type ctxKey int
+
+const (
+	actorKey ctxKey = iota
+	requestIDKey
+	ifMatchKey
+	principalKey
+	tenantKey
+	loggerKey
+)
+
+// AnonymousActor is used when the caller did not identify itself.
+const AnonymousActor = "anonymous"
+
+// WithActor stores the caller identity in the context.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor returns the caller identity stored in the context or AnonymousActor.
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+// WithRequestID stores the request ID in the context.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID stored in the context, if any.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithIfMatch stores the entity tags of the If-Match request header in the context.
+func WithIfMatch(ctx context.Context, etags []string) context.Context {
+	return context.WithValue(ctx, ifMatchKey, etags)
+}
+
+// IfMatch returns the entity tags the caller expects the modified entity to have.
+// ok is false when the request has no precondition.
+func IfMatch(ctx context.Context) (etags []string, ok bool) {
+	etags, _ = ctx.Value(ifMatchKey).([]string)
+	return etags, len(etags) > 0
+}
+
+// WithPrincipal stores the authenticated caller in the context.
+func WithPrincipal(ctx context.Context, principal *model.Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// Principal returns the authenticated caller, ok is false when authentication is disabled.
+func Principal(ctx context.Context) (principal *model.Principal, ok bool) {
+	principal, ok = ctx.Value(principalKey).(*model.Principal)
+	return principal, ok && principal != nil
+}
+
+// WithTenant stores the agency the request acts within in the context.
+func WithTenant(ctx context.Context, tenantID int) context.Context {
+	return context.WithValue(ctx, tenantKey, tenantID)
+}
+
+// Tenant returns the agency the request acts within, ok is false outside of requests.
+func Tenant(ctx context.Context) (tenantID int, ok bool) {
+	tenantID, ok = ctx.Value(tenantKey).(int)
+	return tenantID, ok
+}
+
+// WithLogger stores the logger of the request, carrying its request ID, route and caller.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// Logger returns the logger of the request, or the default logger outside of requests.
+func Logger(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
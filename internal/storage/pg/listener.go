@@ -0,0 +1,62 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/lib/logger/sl"
+)
+
+// EventsChannel is the channel notified with the ID of every new outbox event.
+const EventsChannel = "domain_events"
+
+type eventListener struct {
+	dsn    string
+	logger *slog.Logger
+}
+
+// NewEventListener creates a domain.EventListener on a dedicated LISTEN connection.
+func NewEventListener(dsn string, logger *slog.Logger) domain.EventListener {
+	return &eventListener{dsn: dsn, logger: logger.With("component", "event_listener")}
+}
+
+func (l *eventListener) Listen(ctx context.Context, fn func(eventID int64)) error {
+	listener := pq.NewListener(l.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			l.logger.Warn("Event listener connection problem", sl.Err(err))
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(EventsChannel); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", EventsChannel, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			if n == nil {
+				// The connection was re-established.
+				fn(0)
+				continue
+			}
+			id, err := strconv.ParseInt(n.Extra, 10, 64)
+			if err != nil {
+				l.logger.Warn("Unexpected event notification", "payload", n.Extra)
+				continue
+			}
+			fn(id)
+		case <-time.After(90 * time.Second):
+			// Detect dead connections that did not report an error.
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/alextotalk/feline-intelligence/internal/lib/logger/sl"
+)
+
+// migrationLockID is the key of the advisory lock held while migrating, so replicas started
+// at the same time apply the migrations once.
+const migrationLockID int64 = 0x66656c696e65 // "feline"
+
+// migrationFile matches <version>_<name>.up.sql and <version>_<name>.down.sql.
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is a schema change with the SQL applying and reverting it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus tells whether a migration is applied to the database.
+type MigrationStatus struct {
+	Version int
+	Name    string
+	Applied bool
+}
+
+// Migrator applies migrations and records the schema version in the schema_migrations table
+// of golang-migrate, so it can be used interchangeably with the migrate CLI.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+// NewMigrator reads the migrations in the root of files.
+func NewMigrator(db *sql.DB, files fs.FS, logger *slog.Logger) (*Migrator, error) {
+	const op = "storage.pg.NewMigrator"
+
+	migrations, err := readMigrations(files)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &Migrator{db: db, migrations: migrations, logger: logger.With("component", "migrator")}, nil
+}
+
+// Latest returns the version of the last migration, the schema version the code depends on.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies all pending migrations and returns how many were applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	const op = "storage.pg.Migrator.Up"
+
+	applied := 0
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		version, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if mig.Version <= version {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig.Up, mig.Version); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			m.logger.InfoContext(ctx, "Migration applied", "version", mig.Version, "name", mig.Name)
+			applied++
+		}
+		return nil
+	})
+	if err != nil {
+		return applied, fmt.Errorf("%s: %w", op, err)
+	}
+	return applied, nil
+}
+
+// Down reverts the last steps applied migrations and returns how many were reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	const op = "storage.pg.Migrator.Down"
+
+	reverted := 0
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		version, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			mig := m.migrations[i]
+			if mig.Version > version {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down migration", mig.Version, mig.Name)
+			}
+			previous := 0
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := m.apply(ctx, conn, mig.Down, previous); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			m.logger.InfoContext(ctx, "Migration reverted", "version", mig.Version, "name", mig.Name)
+			reverted++
+		}
+		return nil
+	})
+	if err != nil {
+		return reverted, fmt.Errorf("%s: %w", op, err)
+	}
+	return reverted, nil
+}
+
+// Version returns the schema version of the database, 0 when no migration is applied.
+// dirty is set when a migration run by the migrate CLI failed halfway.
+func (m *Migrator) Version(ctx context.Context) (version int, dirty bool, err error) {
+	const op = "storage.pg.Migrator.Version"
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("%s: %w", op, err)
+	}
+	defer conn.Close()
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return 0, false, fmt.Errorf("%s: %w", op, err)
+	}
+	version, dirty, err = readVersion(ctx, conn)
+	if err != nil {
+		return 0, false, fmt.Errorf("%s: %w", op, err)
+	}
+	return version, dirty, nil
+}
+
+// Status lists the migrations and whether they are applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	version, _, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		statuses = append(statuses, MigrationStatus{Version: mig.Version, Name: mig.Name, Applied: mig.Version <= version})
+	}
+	return statuses, nil
+}
+
+// locked runs fn on a connection holding the migration advisory lock, waiting for
+// migrations run by other replicas to finish.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("lock: %w", err)
+	}
+	defer func() {
+		// The lock is released with the session anyway, e.g. when ctx is already cancelled.
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockID); err != nil {
+			m.logger.WarnContext(ctx, "Failed to release the migration lock", sl.Err(err))
+		}
+	}()
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// apply runs query and records version in one transaction, so a failed migration leaves
+// the schema unchanged.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, query string, version int) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+	if version > 0 {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, version); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// currentVersion returns the schema version, refusing to migrate a dirty database.
+func currentVersion(ctx context.Context, conn *sql.Conn) (int, error) {
+	version, dirty, err := readVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("database is dirty at version %d, fix the schema and the schema_migrations table manually", version)
+	}
+	return version, nil
+}
+
+func ensureVersionTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)`)
+	return err
+}
+
+func readVersion(ctx context.Context, conn *sql.Conn) (version int, dirty bool, err error) {
+	err = conn.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// readMigrations reads the migration files, sorted by version. Every version needs an up migration.
+func readMigrations(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(files, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has different names: %s and %s", version, mig.Name, match[2])
+		}
+		if match[3] == "up" {
+			mig.Up = string(content)
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up migration", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
@@ -0,0 +1,92 @@
+package pg
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/alextotalk/feline-intelligence/migrations"
+)
+
+func TestReadMigrations(t *testing.T) {
+	files := fstest.MapFS{
+		"010_add_index.up.sql":      {Data: []byte("CREATE INDEX")},
+		"002_create_cats.up.sql":    {Data: []byte("CREATE TABLE cats")},
+		"002_create_cats.down.sql":  {Data: []byte("DROP TABLE cats")},
+		"001_init.up.sql":           {Data: []byte("SELECT 1")},
+		"001_init.down.sql":         {Data: []byte("SELECT 0")},
+		"migrations.go":             {Data: []byte("package migrations")},
+		"README.md":                 {},
+		"003_draft.sql":             {},
+		"nested/004_skip.up.sql":    {},
+		"005_sideways.sideways.sql": {},
+	}
+
+	got, err := readMigrations(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Migration{
+		{Version: 1, Name: "init", Up: "SELECT 1", Down: "SELECT 0"},
+		{Version: 2, Name: "create_cats", Up: "CREATE TABLE cats", Down: "DROP TABLE cats"},
+		{Version: 10, Name: "add_index", Up: "CREATE INDEX"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("read %d migrations, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("migration %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReadMigrationsRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		err   string
+	}{
+		{
+			name: "missing up",
+			files: fstest.MapFS{
+				"001_init.down.sql": {Data: []byte("SELECT 0")},
+			},
+			err: "has no up migration",
+		},
+		{
+			name: "name mismatch",
+			files: fstest.MapFS{
+				"001_init.up.sql":     {Data: []byte("SELECT 1")},
+				"001_create.down.sql": {Data: []byte("SELECT 0")},
+			},
+			err: "different names",
+		},
+	}
+	for _, tt := range tests {
+		_, err := readMigrations(tt.files)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	m, err := NewMigrator(nil, migrations.FS, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, mig := range m.migrations {
+		if mig.Version != i+1 {
+			t.Errorf("migration %d_%s follows version %d", mig.Version, mig.Name, i)
+		}
+		if mig.Down == "" {
+			t.Errorf("migration %d_%s has no down migration", mig.Version, mig.Name)
+		}
+	}
+	if m.Latest() != len(m.migrations) {
+		t.Errorf("Latest() = %d, want %d", m.Latest(), len(m.migrations))
+	}
+}
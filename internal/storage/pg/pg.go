@@ -1,17 +1,21 @@
 package pg
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 
 	"github.com/alextotalk/feline-intelligence/internal/config"
 )
 
-func NewPostgres(cfg *config.Config) (*sql.DB, error) {
-	const op = "storage.pg.New"
-
+// DSN builds the connection string of the configured database. Without row-level security
+// every session sees the rows of all agencies (app.all_tenants is sent as a run-time parameter),
+// the queries scope themselves by agency.
+func DSN(cfg *config.Config) string {
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Database.Host,
 		cfg.Database.Port,
@@ -20,8 +24,39 @@ func NewPostgres(cfg *config.Config) (*sql.DB, error) {
 		cfg.Database.DBName,
 		cfg.Database.SSLMode,
 	)
+	if !cfg.Database.RowLevelSecurity {
+		dsn += allTenantsParam
+	}
+	return dsn
+}
+
+// allTenantsParam lets every session of a pool see the rows of all agencies.
+const allTenantsParam = " app.all_tenants=on"
 
-	db, err := sql.Open("postgres", dsn)
+// NewPostgres opens the pool of the API. With row-level security its sessions only see the
+// rows of the agency set by a request.
+func NewPostgres(cfg *config.Config) (*sql.DB, error) {
+	return open(DSN(cfg))
+}
+
+// NewPlatformPostgres opens a pool whose sessions see the rows of every agency, for the
+// background jobs working across agencies (outbox dispatcher, webhook deliverer, purges).
+// Without row-level security every pool does, it returns nil and the API pool is used.
+func NewPlatformPostgres(cfg *config.Config) (*sql.DB, error) {
+	if !cfg.Database.RowLevelSecurity {
+		return nil, nil
+	}
+	return open(DSN(cfg) + allTenantsParam)
+}
+
+func open(dsn string) (*sql.DB, error) {
+	const op = "storage.pg.New"
+
+	// Every query gets a span, the spans of reading rows and resetting sessions are only noise.
+	db, err := otelsql.Open("postgres", dsn,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitRows: true, OmitConnResetSession: true}),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("не вдалося відкрити підключення до Postgres: %s %w", op, err)
 	}
@@ -32,3 +67,21 @@ func NewPostgres(cfg *config.Config) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// CheckRowLevelSecurity fails when the role of the sessions is not subject to row-level
+// security: superusers and roles with BYPASSRLS skip every policy, even forced ones.
+func CheckRowLevelSecurity(ctx context.Context, db *sql.DB) error {
+	const op = "storage.pg.CheckRowLevelSecurity"
+
+	var role string
+	var superuser, bypass bool
+	err := db.QueryRowContext(ctx, `SELECT rolname, rolsuper, rolbypassrls FROM pg_roles WHERE rolname = current_user`).
+		Scan(&role, &superuser, &bypass)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if superuser || bypass {
+		return fmt.Errorf("%s: role %s bypasses row-level security, connect as a role with NOSUPERUSER NOBYPASSRLS", op, role)
+	}
+	return nil
+}
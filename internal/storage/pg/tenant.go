@@ -0,0 +1,148 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+)
+
+// With row-level security (migration 015) a session only sees the agency rows of the agency
+// in app.tenant_id, or of every agency with app.all_tenants on, and none without either.
+
+// requestConn is the connection the queries of a request run on when row-level security is
+// enabled. It is acquired on the first query and app.tenant_id is kept in sync with the agency
+// of the query context, so plain reads are restricted like transactions. Like a transaction
+// it runs one query at a time: the rows of a query must be closed before the next one.
+type requestConn struct {
+	db *sql.DB
+
+	mu     sync.Mutex
+	conn   *sql.Conn
+	tenant string // app.tenant_id of the session
+}
+
+type requestConnKey struct{}
+
+// ScopeRequest returns a context whose queries run on a connection restricted to the agency of
+// the request, and a function releasing the connection once the request is done. Without
+// row-level security it returns ctx unchanged.
+func (t *Transactor) ScopeRequest(ctx context.Context) (context.Context, func()) {
+	if !t.rowLevelSecurity {
+		return ctx, func() {}
+	}
+	rc := &requestConn{db: t.db}
+	return context.WithValue(ctx, requestConnKey{}, rc), func() { rc.release(context.WithoutCancel(ctx)) }
+}
+
+// acquire returns the connection with app.tenant_id set to the agency of ctx.
+func (c *requestConn) acquire(ctx context.Context) (*sql.Conn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		conn, err := c.db.Conn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.conn = conn
+	}
+	var tenant string
+	if id, ok := requestctx.Tenant(ctx); ok {
+		tenant = strconv.Itoa(id)
+	}
+	if tenant != c.tenant {
+		if _, err := c.conn.ExecContext(ctx, `SELECT set_config('app.tenant_id', $1, false)`, tenant); err != nil {
+			return nil, fmt.Errorf("set tenant: %w", err)
+		}
+		c.tenant = tenant
+	}
+	return c.conn, nil
+}
+
+// release clears app.tenant_id and returns the connection to the pool. A connection that
+// cannot be cleared is discarded, the next request must not inherit the agency.
+func (c *requestConn) release(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return
+	}
+	if c.tenant != "" {
+		if _, err := c.conn.ExecContext(ctx, `SELECT set_config('app.tenant_id', '', false)`); err != nil {
+			_ = c.conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}
+	_ = c.conn.Close()
+	c.conn = nil
+	c.tenant = ""
+}
+
+func (c *requestConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	conn, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return conn.ExecContext(ctx, query, args...)
+}
+
+func (c *requestConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	conn, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return conn.QueryContext(ctx, query, args...)
+}
+
+func (c *requestConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	conn, err := c.acquire(ctx)
+	if err != nil {
+		// *sql.Row cannot carry the error. A pooled session has no agency set,
+		// so the query sees no agency rows instead.
+		return c.db.QueryRowContext(ctx, query, args...)
+	}
+	return conn.QueryRowContext(ctx, query, args...)
+}
+
+func (c *requestConn) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	conn, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return conn.BeginTx(ctx, opts)
+}
+
+// BeginTx starts a transaction on the request connection of ctx, or on db when there is none.
+// Use it instead of db.BeginTx, so the transaction is restricted to the agency of the request.
+func BeginTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions) (*sql.Tx, error) {
+	if rc, ok := ctx.Value(requestConnKey{}).(*requestConn); ok {
+		return rc.BeginTx(ctx, opts)
+	}
+	return db.BeginTx(ctx, opts)
+}
+
+// AcrossTenants runs fn in a read-only transaction that sees the rows of every agency, for the
+// queries that intentionally span agencies, e.g. metrics and the event stream. The transaction
+// is carried by the context passed to fn, see Conn.
+func AcrossTenants(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	const op = "storage.pg.AcrossTenants"
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('app.all_tenants', 'on', true)`); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := fn(context.WithValue(ctx, txKey{}, &txState{tx: tx})); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,129 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/lib/pq"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/lib/logger/sl"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+)
+
+// DBTX is implemented by both *sql.DB and *sql.Tx.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// txState is the transaction carried by the context. savepoints counts nested WithinTx calls
+// to give every savepoint a unique name.
+type txState struct {
+	tx         *sql.Tx
+	savepoints int
+}
+
+type txKey struct{}
+
+// Conn returns the transaction stored in ctx by Transactor, the request connection stored
+// by ScopeRequest when there is no transaction, or db when there is neither.
+func Conn(ctx context.Context, db *sql.DB) DBTX {
+	if st, ok := ctx.Value(txKey{}).(*txState); ok {
+		return st.tx
+	}
+	if rc, ok := ctx.Value(requestConnKey{}).(*requestConn); ok {
+		return rc
+	}
+	return db
+}
+
+// Transactor runs functions inside a database transaction carried by the context.
+type Transactor struct {
+	db *sql.DB
+	// rowLevelSecurity sets app.tenant_id in every transaction of a request and on the
+	// request connection (see ScopeRequest), so the row-level security policies only let
+	// it see the rows of its agency.
+	rowLevelSecurity bool
+}
+
+func NewTransactor(db *sql.DB, rowLevelSecurity bool) *Transactor {
+	return &Transactor{db: db, rowLevelSecurity: rowLevelSecurity}
+}
+
+// WithinTx runs fn in a transaction, committing when fn returns nil and rolling back otherwise.
+// Nested calls run in a savepoint of the outer transaction, so a failed nested call is rolled back
+// on its own and the outer transaction can continue.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	const op = "storage.pg.WithinTx"
+
+	if st, ok := ctx.Value(txKey{}).(*txState); ok {
+		return withinSavepoint(ctx, st, fn)
+	}
+
+	tx, err := BeginTx(ctx, t.db, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if tenantID, ok := requestctx.Tenant(ctx); ok && t.rowLevelSecurity {
+		if _, err := tx.ExecContext(ctx, `SELECT set_config('app.tenant_id', $1, true)`, strconv.Itoa(tenantID)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("%s: set tenant: %w", op, err)
+		}
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, &txState{tx: tx})); err != nil {
+		_ = tx.Rollback()
+		requestctx.Logger(ctx).DebugContext(ctx, "Transaction rolled back", sl.Err(err))
+		return TranslateError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, TranslateError(err))
+	}
+	return nil
+}
+
+func withinSavepoint(ctx context.Context, st *txState, fn func(ctx context.Context) error) error {
+	const op = "storage.pg.withinSavepoint"
+
+	st.savepoints++
+	name := fmt.Sprintf("sp_%d", st.savepoints)
+	if _, err := st.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := fn(ctx); err != nil {
+		if _, rbErr := st.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return fmt.Errorf("%s: rollback: %w", op, rbErr)
+		}
+		return TranslateError(err)
+	}
+	if _, err := st.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("%s: release: %w", op, err)
+	}
+	return nil
+}
+
+// TranslateError wraps errors raised by triggers (RAISE EXCEPTION) and constraint violations
+// with domain.ErrRuleViolation, keeping the database message.
+func TranslateError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || errors.Is(err, domain.ErrRuleViolation) {
+		return err
+	}
+	switch pqErr.Code.Class() {
+	case "P0", "23": // plpgsql raise_exception, integrity_constraint_violation
+		return fmt.Errorf("%w: %s", domain.ErrRuleViolation, pqErr.Message)
+	}
+	return err
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
+)
+
+type AgencyUsecase interface {
+	// ResolveTenant returns the agency a request acts within. Callers bound to an agency always
+	// act within it, others within the requested agency or, when none is requested, the default one.
+	ResolveTenant(ctx context.Context, requested *int) (int, error)
+	// CreateAgency and ListAgencies are only available to callers not bound to an agency.
+	CreateAgency(ctx context.Context, agency *model.Agency) error
+	ListAgencies(ctx context.Context) ([]model.Agency, error)
+	GetAgency(ctx context.Context, id int) (*model.Agency, error)
+	// CurrentAgency returns the agency the request acts within.
+	CurrentAgency(ctx context.Context) (*model.Agency, error)
+	// UpdateAgency changes the name and the configuration of the agency.
+	UpdateAgency(ctx context.Context, agency *model.Agency) error
+}
+
+const (
+	defaultMaxTargetsPerMission = 3
+	maxMaxTargetsPerMission     = 100
+)
+
+type agencyUsecase struct {
+	agencyRepo domain.AgencyRepository
+}
+
+func NewAgencyUsecase(ar domain.AgencyRepository) AgencyUsecase {
+	return &agencyUsecase{agencyRepo: ar}
+}
+
+func (u *agencyUsecase) ResolveTenant(ctx context.Context, requested *int) (int, error) {
+	ctx, span := tracing.Start(ctx, "AgencyUsecase.ResolveTenant")
+	defer span.End()
+
+	if p, ok := requestctx.Principal(ctx); ok && p.TenantID != nil {
+		if requested != nil && *requested != *p.TenantID {
+			return 0, fmt.Errorf("%s is bound to agency %d: %w", p.Subject, *p.TenantID, ErrForbidden)
+		}
+		return *p.TenantID, nil
+	}
+	if requested == nil {
+		return model.DefaultAgencyID, nil
+	}
+	if _, err := u.getAgency(ctx, *requested); err != nil {
+		return 0, err
+	}
+	return *requested, nil
+}
+
+func (u *agencyUsecase) CreateAgency(ctx context.Context, agency *model.Agency) error {
+	ctx, span := tracing.Start(ctx, "AgencyUsecase.CreateAgency")
+	defer span.End()
+
+	if err := requirePlatformPermission(ctx, PermAgenciesManage); err != nil {
+		return err
+	}
+	if agency.MaxTargetsPerMission == 0 {
+		agency.MaxTargetsPerMission = defaultMaxTargetsPerMission
+	}
+	if err := validateAgency(agency); err != nil {
+		return err
+	}
+	return u.agencyRepo.Create(ctx, agency)
+}
+
+func (u *agencyUsecase) ListAgencies(ctx context.Context) ([]model.Agency, error) {
+	ctx, span := tracing.Start(ctx, "AgencyUsecase.ListAgencies")
+	defer span.End()
+
+	if err := requirePlatformPermission(ctx, PermAgenciesManage); err != nil {
+		return nil, err
+	}
+	return u.agencyRepo.List(ctx)
+}
+
+func (u *agencyUsecase) GetAgency(ctx context.Context, id int) (*model.Agency, error) {
+	ctx, span := tracing.Start(ctx, "AgencyUsecase.GetAgency")
+	defer span.End()
+
+	if err := authorizeAgency(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.getAgency(ctx, id)
+}
+
+func (u *agencyUsecase) CurrentAgency(ctx context.Context) (*model.Agency, error) {
+	ctx, span := tracing.Start(ctx, "AgencyUsecase.CurrentAgency")
+	defer span.End()
+
+	return u.getAgency(ctx, currentTenant(ctx))
+}
+
+func (u *agencyUsecase) UpdateAgency(ctx context.Context, agency *model.Agency) error {
+	ctx, span := tracing.Start(ctx, "AgencyUsecase.UpdateAgency")
+	defer span.End()
+
+	if err := authorizeAgency(ctx, agency.ID); err != nil {
+		return err
+	}
+	if _, err := u.getAgency(ctx, agency.ID); err != nil {
+		return err
+	}
+	if err := validateAgency(agency); err != nil {
+		return err
+	}
+	return u.agencyRepo.Update(ctx, agency)
+}
+
+func (u *agencyUsecase) getAgency(ctx context.Context, id int) (*model.Agency, error) {
+	agency, err := u.agencyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if agency == nil {
+		return nil, fmt.Errorf("agency %d: %w", id, ErrNotFound)
+	}
+	return agency, nil
+}
+
+// authorizeAgency checks that the caller manages the agency. Callers bound to an agency
+// only manage their own.
+func authorizeAgency(ctx context.Context, id int) error {
+	if err := requirePermission(ctx, PermAgenciesManage); err != nil {
+		return err
+	}
+	if p, ok := requestctx.Principal(ctx); ok && p.TenantID != nil && *p.TenantID != id {
+		return fmt.Errorf("agency %d: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+func validateAgency(agency *model.Agency) error {
+	agency.Name = strings.TrimSpace(agency.Name)
+	if agency.Name == "" {
+		return fmt.Errorf("name is required: %w", ErrInvalidInput)
+	}
+	if agency.MaxTargetsPerMission < 1 || agency.MaxTargetsPerMission > maxMaxTargetsPerMission {
+		return fmt.Errorf("max_targets_per_mission must be between 1 and %d: %w", maxMaxTargetsPerMission, ErrInvalidInput)
+	}
+	return nil
+}
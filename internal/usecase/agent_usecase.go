@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
+)
+
+// AgentUsecase is the self-service of spy cats: everything is scoped to the cat
+// the caller acts as, and target changes go through the ownership checks of MissionUsecase.
+type AgentUsecase interface {
+	Me(ctx context.Context) (*model.AgentProfile, error)
+	// ActiveMission returns the mission the caller's cat is working on.
+	ActiveMission(ctx context.Context) (*model.Mission, error)
+	// UpdateTargetNotes and CompleteTarget only accept targets of the active mission.
+	UpdateTargetNotes(ctx context.Context, targetID int, notes string) error
+	CompleteTarget(ctx context.Context, targetID int) error
+}
+
+type agentUsecase struct {
+	catUC     CatUsecase
+	missionUC MissionUsecase
+}
+
+func NewAgentUsecase(catUC CatUsecase, missionUC MissionUsecase) AgentUsecase {
+	return &agentUsecase{catUC: catUC, missionUC: missionUC}
+}
+
+func (u *agentUsecase) Me(ctx context.Context) (*model.AgentProfile, error) {
+	ctx, span := tracing.Start(ctx, "AgentUsecase.Me")
+	defer span.End()
+
+	principal, catID, err := currentCat(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cat, err := u.catUC.GetCat(ctx, catID)
+	if err != nil {
+		return nil, err
+	}
+	if cat == nil {
+		return nil, fmt.Errorf("cat %d: %w", catID, ErrNotFound)
+	}
+	return &model.AgentProfile{Subject: principal.Subject, Roles: principal.Roles, Cat: *cat}, nil
+}
+
+func (u *agentUsecase) ActiveMission(ctx context.Context) (*model.Mission, error) {
+	ctx, span := tracing.Start(ctx, "AgentUsecase.ActiveMission")
+	defer span.End()
+
+	_, catID, err := currentCat(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mission, err := u.missionUC.GetActiveMission(ctx, catID)
+	if err != nil {
+		return nil, err
+	}
+	if mission == nil {
+		return nil, fmt.Errorf("cat %d has no active mission: %w", catID, ErrNotFound)
+	}
+	return mission, nil
+}
+
+func (u *agentUsecase) UpdateTargetNotes(ctx context.Context, targetID int, notes string) error {
+	ctx, span := tracing.Start(ctx, "AgentUsecase.UpdateTargetNotes")
+	defer span.End()
+
+	if err := u.checkActiveTarget(ctx, targetID); err != nil {
+		return err
+	}
+	return u.missionUC.UpdateTargetNotes(ctx, targetID, notes)
+}
+
+func (u *agentUsecase) CompleteTarget(ctx context.Context, targetID int) error {
+	ctx, span := tracing.Start(ctx, "AgentUsecase.CompleteTarget")
+	defer span.End()
+
+	if err := u.checkActiveTarget(ctx, targetID); err != nil {
+		return err
+	}
+	return u.missionUC.CompleteTarget(ctx, targetID)
+}
+
+// checkActiveTarget makes sure the target belongs to the active mission of the caller's cat.
+// Roles that may change any target pass the ownership checks of MissionUsecase, so they are
+// limited here.
+func (u *agentUsecase) checkActiveTarget(ctx context.Context, targetID int) error {
+	mission, err := u.ActiveMission(ctx)
+	if err != nil {
+		return err
+	}
+	for _, t := range mission.Targets {
+		if t.ID == targetID {
+			return nil
+		}
+	}
+	return fmt.Errorf("target %d is not on your active mission: %w", targetID, ErrNotFound)
+}
+
+// currentCat returns the caller and the cat they act as.
+func currentCat(ctx context.Context) (*model.Principal, int, error) {
+	principal, ok := requestctx.Principal(ctx)
+	if !ok {
+		return nil, 0, fmt.Errorf("the caller is not identified: %w", ErrUnauthenticated)
+	}
+	if principal.CatID == nil {
+		return nil, 0, fmt.Errorf("%s does not act as a cat: %w", principal.Subject, ErrForbidden)
+	}
+	return principal, *principal.CatID, nil
+}
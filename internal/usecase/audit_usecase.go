@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
+)
+
+type AuditUsecase interface {
+	// Record stores a mutation of an entity together with its before/after snapshots.
+	// Nil snapshots are stored as NULL (e.g. "before" of a created entity).
+	Record(ctx context.Context, action, entityType string, entityID int, before, after any) error
+	ListEvents(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error)
+}
+
+type auditUsecase struct {
+	auditRepo domain.AuditRepository
+}
+
+func NewAuditUsecase(ar domain.AuditRepository) AuditUsecase {
+	return &auditUsecase{auditRepo: ar}
+}
+
+func (u *auditUsecase) Record(ctx context.Context, action, entityType string, entityID int, before, after any) error {
+	ctx, span := tracing.Start(ctx, "AuditUsecase.Record")
+	defer span.End()
+
+	beforeJSON, err := snapshot(before)
+	if err != nil {
+		return fmt.Errorf("audit: failed to encode before snapshot: %w", err)
+	}
+	afterJSON, err := snapshot(after)
+	if err != nil {
+		return fmt.Errorf("audit: failed to encode after snapshot: %w", err)
+	}
+
+	event := &model.AuditEvent{
+		Actor:      requestctx.Actor(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		RequestID:  requestctx.RequestID(ctx),
+	}
+	if err := u.auditRepo.Create(ctx, event); err != nil {
+		return fmt.Errorf("audit: failed to record %s: %w", action, err)
+	}
+	return nil
+}
+
+func (u *auditUsecase) ListEvents(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error) {
+	ctx, span := tracing.Start(ctx, "AuditUsecase.ListEvents")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermAuditRead); err != nil {
+		return nil, err
+	}
+	return u.auditRepo.List(ctx, filter)
+}
+
+func snapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/logger/sl"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
+)
+
+type AuthUsecase interface {
+	// AuthenticateAPIKey returns the caller of a usable API key, or ErrUnauthenticated.
+	AuthenticateAPIKey(ctx context.Context, key string) (*model.Principal, error)
+	// AuthenticateToken returns the caller of a valid bearer token, or ErrUnauthenticated.
+	AuthenticateToken(ctx context.Context, token string) (*model.Principal, error)
+	// IssueAPIKey stores a new key for key.Subject (the name when empty) with the given roles.
+	// Agent keys must name the cat they act as. Keys issued by callers bound to an agency are
+	// bound to the same agency. The returned key is the only place the plain key is available.
+	IssueAPIKey(ctx context.Context, key *model.APIKey) (*model.IssuedAPIKey, error)
+	// ListAPIKeys returns the keys of the caller's agency, or all keys for callers not bound to one.
+	ListAPIKeys(ctx context.Context) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) error
+}
+
+// APIKeyPrefix starts every API key, so they are easy to tell apart from bearer tokens
+// and to spot in leaked secrets.
+const APIKeyPrefix = "fik_"
+
+type authUsecase struct {
+	apiKeyRepo domain.APIKeyRepository
+	catRepo    domain.CatRepository
+	agencyRepo domain.AgencyRepository
+	verifier   domain.TokenVerifier
+}
+
+// NewAuthUsecase creates the auth usecase. verifier may be nil, then bearer tokens are rejected.
+func NewAuthUsecase(kr domain.APIKeyRepository, cr domain.CatRepository, ar domain.AgencyRepository, verifier domain.TokenVerifier) AuthUsecase {
+	return &authUsecase{apiKeyRepo: kr, catRepo: cr, agencyRepo: ar, verifier: verifier}
+}
+
+func (u *authUsecase) AuthenticateAPIKey(ctx context.Context, key string) (*model.Principal, error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.AuthenticateAPIKey")
+	defer span.End()
+
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, fmt.Errorf("malformed API key: %w", ErrUnauthenticated)
+	}
+	stored, err := u.apiKeyRepo.GetByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if stored == nil || !stored.Usable(now) {
+		return nil, fmt.Errorf("unknown, expired or revoked API key: %w", ErrUnauthenticated)
+	}
+	// Bookkeeping only, a failed write must not reject the caller.
+	if err := u.apiKeyRepo.TouchLastUsed(ctx, stored.ID, now); err != nil {
+		requestctx.Logger(ctx).WarnContext(ctx, "Failed to record API key use", "api_key_id", stored.ID, sl.Err(err))
+	}
+	return &model.Principal{
+		Subject:  stored.Subject,
+		Method:   model.AuthMethodAPIKey,
+		Roles:    stored.Roles,
+		CatID:    stored.CatID,
+		TenantID: stored.TenantID,
+		APIKeyID: stored.ID,
+	}, nil
+}
+
+func (u *authUsecase) AuthenticateToken(_ context.Context, token string) (*model.Principal, error) {
+	if u.verifier == nil {
+		return nil, fmt.Errorf("bearer tokens are not accepted: %w", ErrUnauthenticated)
+	}
+	principal, err := u.verifier.Verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bearer token: %v: %w", err, ErrUnauthenticated)
+	}
+	return principal, nil
+}
+
+func (u *authUsecase) IssueAPIKey(ctx context.Context, key *model.APIKey) (*model.IssuedAPIKey, error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.IssueAPIKey")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermAPIKeysManage); err != nil {
+		return nil, err
+	}
+	key.Name = strings.TrimSpace(key.Name)
+	key.Subject = strings.TrimSpace(key.Subject)
+	if key.Name == "" {
+		return nil, fmt.Errorf("name is required: %w", ErrInvalidInput)
+	}
+	if key.Subject == "" {
+		key.Subject = key.Name
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expires_at must be in the future: %w", ErrInvalidInput)
+	}
+	if err := u.bindTenant(ctx, key); err != nil {
+		return nil, err
+	}
+	if err := u.validateRoles(ctx, key); err != nil {
+		return nil, err
+	}
+
+	prefix, err := randomHex(4)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	key.Prefix = APIKeyPrefix + prefix
+	plain := key.Prefix + "_" + secret
+	key.KeyHash = hashAPIKey(plain)
+	key.LastUsedAt = nil
+	key.RevokedAt = nil
+	if err := u.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	return &model.IssuedAPIKey{APIKey: *key, Key: plain}, nil
+}
+
+func (u *authUsecase) ListAPIKeys(ctx context.Context) ([]model.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.ListAPIKeys")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermAPIKeysManage); err != nil {
+		return nil, err
+	}
+	return u.apiKeyRepo.List(ctx, callerTenant(ctx))
+}
+
+func (u *authUsecase) RevokeAPIKey(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.RevokeAPIKey")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermAPIKeysManage); err != nil {
+		return err
+	}
+	key, err := u.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if tenantID := callerTenant(ctx); key == nil || tenantID != nil && (key.TenantID == nil || *key.TenantID != *tenantID) {
+		return fmt.Errorf("API key %d: %w", id, ErrNotFound)
+	}
+	return u.apiKeyRepo.Revoke(ctx, id, time.Now())
+}
+
+// bindTenant binds the key to the agency of the caller. Callers not bound to an agency
+// may bind it to any existing agency or leave it unbound.
+func (u *authUsecase) bindTenant(ctx context.Context, key *model.APIKey) error {
+	if tenantID := callerTenant(ctx); tenantID != nil {
+		if key.TenantID != nil && *key.TenantID != *tenantID {
+			return fmt.Errorf("keys can only be issued for agency %d: %w", *tenantID, ErrForbidden)
+		}
+		key.TenantID = tenantID
+		return nil
+	}
+	if key.TenantID == nil {
+		return nil
+	}
+	agency, err := u.agencyRepo.GetByID(ctx, *key.TenantID)
+	if err != nil {
+		return err
+	}
+	if agency == nil {
+		return fmt.Errorf("agency %d does not exist: %w", *key.TenantID, ErrInvalidInput)
+	}
+	return nil
+}
+
+// validateRoles checks the roles of a key. Agent keys act as a cat, which has to exist,
+// and are always bound to the agency of the cat.
+func (u *authUsecase) validateRoles(ctx context.Context, key *model.APIKey) error {
+	if len(key.Roles) == 0 {
+		return fmt.Errorf("at least one role is required: %w", ErrInvalidInput)
+	}
+	for _, role := range key.Roles {
+		if !slices.Contains(model.Roles, role) {
+			return fmt.Errorf("unknown role %q: %w", role, ErrInvalidInput)
+		}
+	}
+	agent := slices.Contains(key.Roles, model.RoleAgent)
+	switch {
+	case agent && key.CatID == nil:
+		return fmt.Errorf("agent keys require cat_id: %w", ErrInvalidInput)
+	case !agent && key.CatID != nil:
+		return fmt.Errorf("cat_id is only allowed for agent keys: %w", ErrInvalidInput)
+	case agent:
+		if key.TenantID == nil {
+			tenantID := currentTenant(ctx)
+			key.TenantID = &tenantID
+		}
+		cat, err := u.catRepo.GetByID(requestctx.WithTenant(ctx, *key.TenantID), *key.CatID)
+		if err != nil {
+			return err
+		}
+		if cat == nil {
+			return fmt.Errorf("cat %d does not exist: %w", *key.CatID, ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// hashAPIKey returns the stored form of a key. Keys are long random strings,
+// so a plain SHA-256 is enough and allows looking them up by hash.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/infrastructure/auth"
+)
+
+type fakeAPIKeyRepo struct {
+	domain.APIKeyRepository
+	keys map[string]model.APIKey
+}
+
+func (r *fakeAPIKeyRepo) GetByHash(_ context.Context, keyHash string) (*model.APIKey, error) {
+	if k, ok := r.keys[keyHash]; ok {
+		return &k, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeAPIKeyRepo) TouchLastUsed(context.Context, int, time.Time) error { return nil }
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	repo := &fakeAPIKeyRepo{keys: map[string]model.APIKey{
+		hashAPIKey("fik_valid"):   {ID: 1, Subject: "handler:alice", Roles: []string{model.RoleHandler}, ExpiresAt: &future},
+		hashAPIKey("fik_expired"): {ID: 2, Subject: "handler:bob", ExpiresAt: &past},
+		hashAPIKey("fik_revoked"): {ID: 3, Subject: "handler:carol", RevokedAt: &past},
+	}}
+	uc := NewAuthUsecase(repo, nil, nil, nil)
+
+	tests := []struct {
+		key   string
+		valid bool
+	}{
+		{"fik_valid", true},
+		{"fik_expired", false},
+		{"fik_revoked", false},
+		{"fik_unknown", false},
+		{"valid", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			principal, err := uc.AuthenticateAPIKey(context.Background(), tt.key)
+			if tt.valid {
+				if err != nil || principal.Subject != "handler:alice" || principal.APIKeyID != 1 {
+					t.Errorf("principal = %+v, err = %v, want handler:alice", principal, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrUnauthenticated) {
+				t.Errorf("err = %v, want ErrUnauthenticated", err)
+			}
+		})
+	}
+}
+
+func TestAuthenticateTokenRejectsOtherAlgorithms(t *testing.T) {
+	verifier, err := auth.NewJWTVerifier(auth.JWTConfig{HS256Secret: "test-secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc := NewAuthUsecase(&fakeAPIKeyRepo{}, nil, nil, verifier)
+	claims := jwt.MapClaims{"sub": "handler:alice", "exp": time.Now().Add(time.Hour).Unix()}
+
+	valid, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	if _, err := uc.AuthenticateToken(context.Background(), valid); err != nil {
+		t.Fatalf("HS256 token rejected: %v", err)
+	}
+	for _, method := range []jwt.SigningMethod{jwt.SigningMethodHS384, jwt.SigningMethodNone} {
+		var key any = []byte("test-secret")
+		if method == jwt.SigningMethodNone {
+			key = jwt.UnsafeAllowNoneSignatureType
+		}
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := uc.AuthenticateToken(context.Background(), token); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("%s token: err = %v, want ErrUnauthenticated", method.Alg(), err)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+)
+
+// Permission is an operation, or a group of operations, roles are granted.
+type Permission string
+
+const (
+	PermCatsRead       Permission = "cats:read"
+	PermCatsWrite      Permission = "cats:write"
+	PermCatsDelete     Permission = "cats:delete"
+	PermSalaryManage   Permission = "salary:manage"
+	PermMissionsRead   Permission = "missions:read"
+	PermMissionsWrite  Permission = "missions:write"
+	PermTargetsUpdate  Permission = "targets:update"
+	PermAuditRead      Permission = "audit:read"
+	PermReportsRead    Permission = "reports:read"
+	PermDataImport     Permission = "data:import"
+	PermDataExport     Permission = "data:export"
+	PermEventsRead     Permission = "events:read"
+	PermWebhooksManage Permission = "webhooks:manage"
+	PermAPIKeysManage  Permission = "apikeys:manage"
+	// PermAgenciesManage is only granted to admins.
+	PermAgenciesManage Permission = "agencies:manage"
+)
+
+// scope limits where a granted permission applies.
+type scope int
+
+const (
+	scopeNone scope = iota
+	// scopeOwn grants the permission on the caller's own cat and the missions assigned to it.
+	scopeOwn
+	scopeAll
+)
+
+// rolePermissions is the RBAC policy. Admins are granted every permission.
+var rolePermissions = map[string]map[Permission]scope{
+	model.RoleHandler: {
+		PermCatsRead:      scopeAll,
+		PermCatsWrite:     scopeAll,
+		PermCatsDelete:    scopeAll,
+		PermSalaryManage:  scopeAll,
+		PermMissionsRead:  scopeAll,
+		PermMissionsWrite: scopeAll,
+		PermTargetsUpdate: scopeAll,
+		PermAuditRead:     scopeAll,
+		PermReportsRead:   scopeAll,
+		PermDataImport:    scopeAll,
+		PermDataExport:    scopeAll,
+		PermEventsRead:    scopeAll,
+	},
+	model.RoleAuditor: {
+		PermCatsRead:     scopeAll,
+		PermMissionsRead: scopeAll,
+		PermAuditRead:    scopeAll,
+		PermReportsRead:  scopeAll,
+		PermDataExport:   scopeAll,
+		PermEventsRead:   scopeAll,
+	},
+	model.RoleAgent: {
+		PermCatsRead:      scopeOwn,
+		PermMissionsRead:  scopeOwn,
+		PermTargetsUpdate: scopeOwn,
+	},
+}
+
+// grantedScope returns the widest scope any role of the principal grants perm with.
+func grantedScope(p *model.Principal, perm Permission) scope {
+	granted := scopeNone
+	for _, role := range p.Roles {
+		if role == model.RoleAdmin {
+			return scopeAll
+		}
+		granted = max(granted, rolePermissions[role][perm])
+	}
+	return granted
+}
+
+// authorize checks that the caller may perform an operation requiring perm. ownCatID is
+// set when the caller may only perform it on that cat and its missions, the ownership
+// is then up to the caller of authorize (see authorizeCat and authorizeMission).
+// Requests without a principal, i.e. with authentication disabled, and background jobs
+// are not restricted.
+func authorize(ctx context.Context, perm Permission) (ownCatID *int, err error) {
+	p, ok := requestctx.Principal(ctx)
+	if !ok {
+		return nil, nil
+	}
+	switch grantedScope(p, perm) {
+	case scopeAll:
+		return nil, nil
+	case scopeOwn:
+		if p.CatID == nil {
+			return nil, fmt.Errorf("%s is only granted for an own cat, %s has none: %w", perm, p.Subject, ErrForbidden)
+		}
+		return p.CatID, nil
+	}
+	return nil, fmt.Errorf("%s is not allowed to %s: %w", p.Subject, perm, ErrForbidden)
+}
+
+// requirePermission checks that the caller is granted perm without limits, for operations
+// that do not belong to a single cat.
+func requirePermission(ctx context.Context, perm Permission) error {
+	own, err := authorize(ctx, perm)
+	if err == nil && own != nil {
+		return fmt.Errorf("%s is only granted for an own cat: %w", perm, ErrForbidden)
+	}
+	return err
+}
+
+// requirePlatformPermission checks perm like requirePermission and additionally that the
+// caller is not bound to an agency, for operations that span all agencies.
+func requirePlatformPermission(ctx context.Context, perm Permission) error {
+	if err := requirePermission(ctx, perm); err != nil {
+		return err
+	}
+	if p, ok := requestctx.Principal(ctx); ok && p.TenantID != nil {
+		return fmt.Errorf("%s is bound to agency %d, %s spans all agencies: %w", p.Subject, *p.TenantID, perm, ErrForbidden)
+	}
+	return nil
+}
+
+// currentTenant returns the agency the request acts within, the default one outside of requests.
+func currentTenant(ctx context.Context) int {
+	if id, ok := requestctx.Tenant(ctx); ok {
+		return id
+	}
+	return model.DefaultAgencyID
+}
+
+// authorizeCat checks perm on the cat.
+func authorizeCat(ctx context.Context, perm Permission, catID int) error {
+	own, err := authorize(ctx, perm)
+	if err != nil || own == nil {
+		return err
+	}
+	if *own != catID {
+		return fmt.Errorf("cat %d is not yours: %w", catID, ErrForbidden)
+	}
+	return nil
+}
+
+// authorizeMission checks perm on the mission. Callers limited to their own cat may only
+// access missions assigned to it, and change them only while the mission is active.
+func authorizeMission(ctx context.Context, perm Permission, mission *model.Mission) error {
+	own, err := authorize(ctx, perm)
+	if err != nil || own == nil {
+		return err
+	}
+	if mission.CatID == nil || *mission.CatID != *own {
+		return fmt.Errorf("mission %d is not assigned to you: %w", mission.ID, ErrForbidden)
+	}
+	if perm != PermMissionsRead && mission.Completed {
+		return fmt.Errorf("mission %d is not active: %w", mission.ID, ErrForbidden)
+	}
+	return nil
+}
+
+// callerTenant returns the agency the caller is bound to, nil when it is not bound to one.
+func callerTenant(ctx context.Context) *int {
+	if p, ok := requestctx.Principal(ctx); ok {
+		return p.TenantID
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func withPrincipal(p *model.Principal) context.Context {
+	return requestctx.WithPrincipal(context.Background(), p)
+}
+
+func TestGrantedScope(t *testing.T) {
+	tests := []struct {
+		roles []string
+		perm  Permission
+		want  scope
+	}{
+		{[]string{model.RoleAdmin}, PermAgenciesManage, scopeAll},
+		{[]string{model.RoleHandler}, PermCatsDelete, scopeAll},
+		{[]string{model.RoleHandler}, PermAPIKeysManage, scopeNone},
+		{[]string{model.RoleAuditor}, PermReportsRead, scopeAll},
+		{[]string{model.RoleAuditor}, PermCatsWrite, scopeNone},
+		{[]string{model.RoleAgent}, PermTargetsUpdate, scopeOwn},
+		{[]string{model.RoleAgent}, PermAuditRead, scopeNone},
+		// The widest grant of all roles wins.
+		{[]string{model.RoleAgent, model.RoleAuditor}, PermCatsRead, scopeAll},
+		{[]string{"unknown"}, PermCatsRead, scopeNone},
+		{nil, PermCatsRead, scopeNone},
+	}
+	for _, tt := range tests {
+		if got := grantedScope(&model.Principal{Roles: tt.roles}, tt.perm); got != tt.want {
+			t.Errorf("grantedScope(%v, %s) = %d, want %d", tt.roles, tt.perm, got, tt.want)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	if own, err := authorize(context.Background(), PermAgenciesManage); own != nil || err != nil {
+		t.Errorf("without a principal: own = %v, err = %v, want unrestricted", own, err)
+	}
+
+	agent := withPrincipal(&model.Principal{Subject: "agent:tom", Roles: []string{model.RoleAgent}, CatID: ptr(3)})
+	if own, err := authorize(agent, PermCatsRead); err != nil || own == nil || *own != 3 {
+		t.Errorf("agent reading cats: own = %v, err = %v, want cat 3", own, err)
+	}
+	if _, err := authorize(agent, PermCatsWrite); !errors.Is(err, ErrForbidden) {
+		t.Errorf("agent writing cats: err = %v, want ErrForbidden", err)
+	}
+
+	catless := withPrincipal(&model.Principal{Subject: "agent:nobody", Roles: []string{model.RoleAgent}})
+	if _, err := authorize(catless, PermCatsRead); !errors.Is(err, ErrForbidden) {
+		t.Errorf("agent without a cat: err = %v, want ErrForbidden", err)
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *model.Principal
+		perm      Permission
+		platform  bool
+		allowed   bool
+	}{
+		{"handler", &model.Principal{Roles: []string{model.RoleHandler}}, PermDataExport, false, true},
+		{"own grant only", &model.Principal{Roles: []string{model.RoleAgent}, CatID: ptr(3)}, PermMissionsRead, false, false},
+		{"not granted", &model.Principal{Roles: []string{model.RoleAuditor}}, PermWebhooksManage, false, false},
+		{"platform admin", &model.Principal{Roles: []string{model.RoleAdmin}}, PermAgenciesManage, true, true},
+		{"agency admin", &model.Principal{Roles: []string{model.RoleAdmin}, TenantID: ptr(2)}, PermAgenciesManage, true, false},
+		{"platform handler", &model.Principal{Roles: []string{model.RoleHandler}}, PermAgenciesManage, true, false},
+	}
+	for _, tt := range tests {
+		check := requirePermission
+		if tt.platform {
+			check = requirePlatformPermission
+		}
+		err := check(withPrincipal(tt.principal), tt.perm)
+		if tt.allowed && err != nil {
+			t.Errorf("%s: err = %v, want allowed", tt.name, err)
+		}
+		if !tt.allowed && !errors.Is(err, ErrForbidden) {
+			t.Errorf("%s: err = %v, want ErrForbidden", tt.name, err)
+		}
+	}
+}
+
+func TestAuthorizeCatAndMission(t *testing.T) {
+	agent := withPrincipal(&model.Principal{Roles: []string{model.RoleAgent}, CatID: ptr(3)})
+	handler := withPrincipal(&model.Principal{Roles: []string{model.RoleHandler}})
+
+	if err := authorizeCat(agent, PermCatsRead, 3); err != nil {
+		t.Errorf("agent reading own cat: %v", err)
+	}
+	if err := authorizeCat(agent, PermCatsRead, 4); !errors.Is(err, ErrForbidden) {
+		t.Errorf("agent reading another cat: err = %v, want ErrForbidden", err)
+	}
+
+	own := &model.Mission{ID: 1, CatID: ptr(3)}
+	other := &model.Mission{ID: 2, CatID: ptr(4)}
+	unassigned := &model.Mission{ID: 3}
+	completed := &model.Mission{ID: 4, CatID: ptr(3), Completed: true}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		perm    Permission
+		mission *model.Mission
+		allowed bool
+	}{
+		{"own mission", agent, PermTargetsUpdate, own, true},
+		{"another cat's mission", agent, PermMissionsRead, other, false},
+		{"unassigned mission", agent, PermMissionsRead, unassigned, false},
+		{"reading a completed mission", agent, PermMissionsRead, completed, true},
+		{"updating a completed mission", agent, PermTargetsUpdate, completed, false},
+		{"handler on any mission", handler, PermTargetsUpdate, other, true},
+	}
+	for _, tt := range tests {
+		err := authorizeMission(tt.ctx, tt.perm, tt.mission)
+		if tt.allowed && err != nil {
+			t.Errorf("%s: err = %v, want allowed", tt.name, err)
+		}
+		if !tt.allowed && !errors.Is(err, ErrForbidden) {
+			t.Errorf("%s: err = %v, want ErrForbidden", tt.name, err)
+		}
+	}
+}
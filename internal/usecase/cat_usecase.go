@@ -3,34 +3,119 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/alextotalk/feline-intelligence/internal/domain"
 	"github.com/alextotalk/feline-intelligence/internal/domain/model"
 	"github.com/alextotalk/feline-intelligence/internal/infrastructure/catapi"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
 )
 
 type CatUsecase interface {
 	CreateCat(ctx context.Context, cat *model.Cat) error
 	GetCat(ctx context.Context, id int) (*model.Cat, error)
 	ListCats(ctx context.Context) ([]model.Cat, error)
-	UpdateCatSalary(ctx context.Context, catID int, newSalary float64) error
+	// UpdateCatSalary records the salary change in the history and applies it,
+	// or leaves it pending when the raise exceeds the SalaryPolicy threshold.
+	UpdateCatSalary(ctx context.Context, catID int, change *model.SalaryChange) error
+	GetSalaryHistory(ctx context.Context, catID int) ([]model.SalaryChange, error)
+	ApproveSalaryChange(ctx context.Context, catID, changeID int) (*model.SalaryChange, error)
+	RejectSalaryChange(ctx context.Context, catID, changeID int) (*model.SalaryChange, error)
 	DeleteCat(ctx context.Context, catID int) error
 }
 
+// SalaryPolicy configures allowed salary currencies and when salary changes need approval.
+type SalaryPolicy struct {
+	// ApprovalThresholdPercent is the raise above which a change becomes pending. Zero disables approvals.
+	ApprovalThresholdPercent float64
+	// Currencies lists allowed salary currencies. The first one is used when a cat is created without currency.
+	Currencies []string
+}
+
+// RequiresApproval reports whether changing the salary from previous to next has to be approved.
+func (p SalaryPolicy) RequiresApproval(previous, next model.Amount) bool {
+	if p.ApprovalThresholdPercent <= 0 || next <= previous {
+		return false
+	}
+	if previous <= 0 {
+		return true
+	}
+	return float64(next-previous)/float64(previous)*100 > p.ApprovalThresholdPercent
+}
+
+// normalizeSalary validates the amount and the currency, filling the currency with fallback when empty.
+func (p SalaryPolicy) normalizeSalary(m *model.Money, fallback string) error {
+	if m.Amount < 0 {
+		return fmt.Errorf("salary must not be negative: %w", ErrInvalidInput)
+	}
+	if m.Amount > model.MaxAmount {
+		return fmt.Errorf("salary must not exceed %s: %w", model.MaxAmount, ErrInvalidInput)
+	}
+	m.Currency = strings.ToUpper(strings.TrimSpace(m.Currency))
+	if m.Currency == "" {
+		m.Currency = fallback
+	}
+	if len(p.Currencies) > 0 && !slices.Contains(p.Currencies, m.Currency) {
+		return fmt.Errorf("currency %q is not allowed, expected one of %s: %w",
+			m.Currency, strings.Join(p.Currencies, ", "), ErrInvalidInput)
+	}
+	return nil
+}
+
+func (p SalaryPolicy) defaultCurrency() string {
+	if len(p.Currencies) == 0 {
+		return model.DefaultCurrency
+	}
+	return p.Currencies[0]
+}
+
 type catUsecase struct {
-	catRepo domain.CatRepository
-	catAPI  catapi.CatAPI
+	catRepo        domain.CatRepository
+	salaryRepo     domain.SalaryHistoryRepository
+	assignmentRepo domain.AssignmentRepository
+	tx             domain.Transactor
+	catAPI         catapi.CatAPI
+	audit          AuditUsecase
+	events         EventUsecase
+	policy         SalaryPolicy
 }
 
-func NewCatUsecase(cr domain.CatRepository, catAPI catapi.CatAPI) CatUsecase {
+func NewCatUsecase(
+	cr domain.CatRepository,
+	sr domain.SalaryHistoryRepository,
+	ar domain.AssignmentRepository,
+	tx domain.Transactor,
+	catAPI catapi.CatAPI,
+	audit AuditUsecase,
+	events EventUsecase,
+	policy SalaryPolicy,
+) CatUsecase {
 	return &catUsecase{
-		catRepo: cr,
-		catAPI:  catAPI,
+		catRepo:        cr,
+		salaryRepo:     sr,
+		assignmentRepo: ar,
+		tx:             tx,
+		catAPI:         catAPI,
+		audit:          audit,
+		events:         events,
+		policy:         policy,
 	}
 }
 
 // CreateCat Creates a cat by checking whether the rock is valid (through thecatapi).
 func (u *catUsecase) CreateCat(ctx context.Context, cat *model.Cat) error {
+	ctx, span := tracing.Start(ctx, "CatUsecase.CreateCat")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermCatsWrite); err != nil {
+		return err
+	}
+	if err := u.policy.normalizeSalary(&cat.Salary, u.policy.defaultCurrency()); err != nil {
+		return err
+	}
 	valid, err := u.catAPI.IsBreedValid(ctx, cat.Breed)
 	if err != nil {
 		return fmt.Errorf("failed to validate cat breed: %w", err)
@@ -38,26 +123,249 @@ func (u *catUsecase) CreateCat(ctx context.Context, cat *model.Cat) error {
 	if !valid {
 		return fmt.Errorf("breed '%s' is not a valid cat breed", cat.Breed)
 	}
-	return u.catRepo.Create(cat)
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.catRepo.Create(ctx, cat); err != nil {
+			return err
+		}
+		if err := u.audit.Record(ctx, model.AuditActionCatCreate, model.AuditEntityCat, cat.ID, nil, cat); err != nil {
+			return err
+		}
+		return u.events.Publish(ctx, model.EventCatCreated, model.AuditEntityCat, cat.ID, cat)
+	})
 }
 
 func (u *catUsecase) GetCat(ctx context.Context, id int) (*model.Cat, error) {
-	return u.catRepo.GetByID(id)
+	ctx, span := tracing.Start(ctx, "CatUsecase.GetCat")
+	defer span.End()
+
+	if err := authorizeCat(ctx, PermCatsRead, id); err != nil {
+		return nil, err
+	}
+	return u.catRepo.GetByID(ctx, id)
 }
 
 func (u *catUsecase) ListCats(ctx context.Context) ([]model.Cat, error) {
-	return u.catRepo.GetAll()
-}
+	ctx, span := tracing.Start(ctx, "CatUsecase.ListCats")
+	defer span.End()
 
-func (u *catUsecase) UpdateCatSalary(ctx context.Context, catID int, newSalary float64) error {
-	cat, err := u.catRepo.GetByID(catID)
+	own, err := authorize(ctx, PermCatsRead)
 	if err != nil {
+		return nil, err
+	}
+	if own != nil {
+		// Agents only see themselves.
+		cat, err := u.catRepo.GetByID(ctx, *own)
+		if err != nil || cat == nil {
+			return nil, err
+		}
+		return []model.Cat{*cat}, nil
+	}
+	return u.catRepo.GetAll(ctx)
+}
+
+func (u *catUsecase) UpdateCatSalary(ctx context.Context, catID int, change *model.SalaryChange) error {
+	ctx, span := tracing.Start(ctx, "CatUsecase.UpdateCatSalary")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermSalaryManage); err != nil {
 		return err
 	}
-	cat.Salary = newSalary
-	return u.catRepo.Update(cat)
+	// Changes take effect when they are applied, so they cannot be scheduled for later.
+	if today := time.Now().UTC().Truncate(24 * time.Hour); change.EffectiveDate.After(today) {
+		return fmt.Errorf("effective date %s is in the future: %w", change.EffectiveDate.Format(time.DateOnly), ErrInvalidInput)
+	}
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		cat, err := u.getCat(ctx, catID)
+		if err != nil {
+			return err
+		}
+		if err := checkPrecondition(ctx, model.AuditEntityCat, catID, cat.Version); err != nil {
+			return err
+		}
+		if err := u.policy.normalizeSalary(&change.NewSalary, cat.Salary.Currency); err != nil {
+			return err
+		}
+		if change.NewSalary.Currency != cat.Salary.Currency {
+			return fmt.Errorf("salary currency of cat %d is %s, cannot change it to %s: %w",
+				catID, cat.Salary.Currency, change.NewSalary.Currency, ErrInvalidInput)
+		}
+		pending, err := u.salaryRepo.GetPendingByCat(ctx, catID)
+		if err != nil {
+			return err
+		}
+		if pending != nil {
+			return fmt.Errorf("cat %d already has salary change %d waiting for approval: %w", catID, pending.ID, ErrConflict)
+		}
+
+		change.CatID = catID
+		change.PreviousSalary = cat.Salary
+		change.RequestedBy = requestctx.Actor(ctx)
+		if change.EffectiveDate.IsZero() {
+			change.EffectiveDate = time.Now()
+		}
+
+		if u.policy.RequiresApproval(cat.Salary.Amount, change.NewSalary.Amount) {
+			change.Status = model.SalaryChangePending
+			if err := u.salaryRepo.Create(ctx, change); err != nil {
+				return err
+			}
+			return u.audit.Record(ctx, model.AuditActionCatRequestSalary, model.AuditEntityCat, catID, nil, change)
+		}
+
+		change.Status = model.SalaryChangeApplied
+		if err := u.salaryRepo.Create(ctx, change); err != nil {
+			return err
+		}
+		return u.applySalary(ctx, cat, change.NewSalary, model.AuditActionCatUpdateSalary)
+	})
+}
+
+func (u *catUsecase) GetSalaryHistory(ctx context.Context, catID int) ([]model.SalaryChange, error) {
+	ctx, span := tracing.Start(ctx, "CatUsecase.GetSalaryHistory")
+	defer span.End()
+
+	if err := authorizeCat(ctx, PermCatsRead, catID); err != nil {
+		return nil, err
+	}
+	if _, err := u.getCat(ctx, catID); err != nil {
+		return nil, err
+	}
+	return u.salaryRepo.ListByCat(ctx, catID)
+}
+
+func (u *catUsecase) ApproveSalaryChange(ctx context.Context, catID, changeID int) (*model.SalaryChange, error) {
+	ctx, span := tracing.Start(ctx, "CatUsecase.ApproveSalaryChange")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermSalaryManage); err != nil {
+		return nil, err
+	}
+	var change *model.SalaryChange
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if change, err = u.decideSalaryChange(ctx, catID, changeID, model.SalaryChangeApplied); err != nil {
+			return err
+		}
+		cat, err := u.getCat(ctx, catID)
+		if err != nil {
+			return err
+		}
+		if cat.Salary != change.PreviousSalary {
+			return fmt.Errorf("salary of cat %d changed since the request was made: %w", catID, ErrConflict)
+		}
+		if err := u.audit.Record(ctx, model.AuditActionCatApproveSalary, model.AuditEntityCat, catID, nil, change); err != nil {
+			return err
+		}
+		return u.applySalary(ctx, cat, change.NewSalary, model.AuditActionCatUpdateSalary)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+func (u *catUsecase) RejectSalaryChange(ctx context.Context, catID, changeID int) (*model.SalaryChange, error) {
+	ctx, span := tracing.Start(ctx, "CatUsecase.RejectSalaryChange")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermSalaryManage); err != nil {
+		return nil, err
+	}
+	var change *model.SalaryChange
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if change, err = u.decideSalaryChange(ctx, catID, changeID, model.SalaryChangeRejected); err != nil {
+			return err
+		}
+		return u.audit.Record(ctx, model.AuditActionCatRejectSalary, model.AuditEntityCat, catID, nil, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return change, nil
 }
 
 func (u *catUsecase) DeleteCat(ctx context.Context, catID int) error {
-	return u.catRepo.Delete(catID)
+	ctx, span := tracing.Start(ctx, "CatUsecase.DeleteCat")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermCatsDelete); err != nil {
+		return err
+	}
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := u.catRepo.GetByID(ctx, catID)
+		if err != nil {
+			return err
+		}
+		if before != nil {
+			if err := checkPrecondition(ctx, model.AuditEntityCat, catID, before.Version); err != nil {
+				return err
+			}
+		}
+		if err := u.catRepo.Delete(ctx, catID); err != nil {
+			return err
+		}
+		if err := u.assignmentRepo.CloseByCat(ctx, catID, model.UnassignReasonCatDeleted); err != nil {
+			return err
+		}
+		if err := u.audit.Record(ctx, model.AuditActionCatDelete, model.AuditEntityCat, catID, before, nil); err != nil {
+			return err
+		}
+		return u.events.Publish(ctx, model.EventCatDeleted, model.AuditEntityCat, catID, before)
+	})
+}
+
+func (u *catUsecase) getCat(ctx context.Context, catID int) (*model.Cat, error) {
+	cat, err := u.catRepo.GetByID(ctx, catID)
+	if err != nil {
+		return nil, err
+	}
+	if cat == nil {
+		return nil, fmt.Errorf("cat %d: %w", catID, ErrNotFound)
+	}
+	return cat, nil
+}
+
+func (u *catUsecase) applySalary(ctx context.Context, cat *model.Cat, salary model.Money, action string) error {
+	before := *cat
+	cat.Salary = salary
+	if err := u.catRepo.Update(ctx, cat); err != nil {
+		return err
+	}
+	if err := u.audit.Record(ctx, action, model.AuditEntityCat, cat.ID, before, cat); err != nil {
+		return err
+	}
+	return u.events.Publish(ctx, model.EventCatSalaryChanged, model.AuditEntityCat, cat.ID, cat)
+}
+
+// decideSalaryChange moves a pending salary change of the cat to the given status.
+func (u *catUsecase) decideSalaryChange(ctx context.Context, catID, changeID int, status string) (*model.SalaryChange, error) {
+	change, err := u.salaryRepo.GetByID(ctx, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if change == nil || change.CatID != catID {
+		return nil, fmt.Errorf("salary change %d of cat %d: %w", changeID, catID, ErrNotFound)
+	}
+	if change.Status != model.SalaryChangePending {
+		return nil, fmt.Errorf("salary change %d is already %s: %w", changeID, change.Status, ErrConflict)
+	}
+
+	decidedBy := requestctx.Actor(ctx)
+	// The approval is the second pair of eyes, the requester may only withdraw the change.
+	if status == model.SalaryChangeApplied && decidedBy == change.RequestedBy {
+		return nil, fmt.Errorf("salary change %d was requested by %s, who cannot approve it: %w", changeID, decidedBy, ErrForbidden)
+	}
+
+	now := time.Now()
+	change.Status = status
+	change.DecidedBy = &decidedBy
+	change.DecidedAt = &now
+	if status == model.SalaryChangeApplied && change.EffectiveDate.Before(now) {
+		change.EffectiveDate = now
+	}
+	if err := u.salaryRepo.UpdateStatus(ctx, change); err != nil {
+		return nil, err
+	}
+	return change, nil
 }
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+)
+
+// fakeCatRepo keeps the cats in memory and bumps their version on update like the pg repository.
+type fakeCatRepo struct {
+	domain.CatRepository
+	cats map[int]*model.Cat
+}
+
+func (r *fakeCatRepo) GetByID(_ context.Context, id int) (*model.Cat, error) {
+	if cat, ok := r.cats[id]; ok {
+		c := *cat
+		return &c, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeCatRepo) Update(_ context.Context, cat *model.Cat) error {
+	stored, ok := r.cats[cat.ID]
+	if !ok || stored.Version != cat.Version {
+		return domain.ErrVersionConflict
+	}
+	cat.Version++
+	c := *cat
+	r.cats[cat.ID] = &c
+	return nil
+}
+
+func (r *fakeCatRepo) Delete(_ context.Context, id int) error {
+	delete(r.cats, id)
+	return nil
+}
+
+type fakeSalaryRepo struct {
+	domain.SalaryHistoryRepository
+	changes []*model.SalaryChange
+}
+
+func (r *fakeSalaryRepo) Create(_ context.Context, change *model.SalaryChange) error {
+	change.ID = len(r.changes) + 1
+	c := *change
+	r.changes = append(r.changes, &c)
+	return nil
+}
+
+func (r *fakeSalaryRepo) GetByID(_ context.Context, id int) (*model.SalaryChange, error) {
+	if id < 1 || id > len(r.changes) {
+		return nil, nil
+	}
+	c := *r.changes[id-1]
+	return &c, nil
+}
+
+func (r *fakeSalaryRepo) GetPendingByCat(_ context.Context, catID int) (*model.SalaryChange, error) {
+	for _, c := range r.changes {
+		if c.CatID == catID && c.Status == model.SalaryChangePending {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeSalaryRepo) UpdateStatus(_ context.Context, change *model.SalaryChange) error {
+	c := *change
+	r.changes[change.ID-1] = &c
+	return nil
+}
+
+type fakeAssignmentRepo struct {
+	domain.AssignmentRepository
+}
+
+func (fakeAssignmentRepo) CloseByCat(context.Context, int, string) error { return nil }
+
+// fakeAudit records the actions of the audit events.
+type fakeAudit struct {
+	AuditUsecase
+	actions []string
+}
+
+func (a *fakeAudit) Record(_ context.Context, action, _ string, _ int, _, _ any) error {
+	a.actions = append(a.actions, action)
+	return nil
+}
+
+// fakeEvents records the types of the published events.
+type fakeEvents struct {
+	types []string
+}
+
+func (e *fakeEvents) Publish(_ context.Context, eventType, _ string, _ int, _ any) error {
+	e.types = append(e.types, eventType)
+	return nil
+}
+
+func newTestCatUsecase(cats ...model.Cat) (CatUsecase, *fakeCatRepo, *fakeSalaryRepo) {
+	catRepo := &fakeCatRepo{cats: make(map[int]*model.Cat)}
+	for _, cat := range cats {
+		catRepo.cats[cat.ID] = &cat
+	}
+	salaryRepo := &fakeSalaryRepo{}
+	uc := NewCatUsecase(catRepo, salaryRepo, fakeAssignmentRepo{}, fakeTransactor{}, nil, &fakeAudit{}, &fakeEvents{},
+		SalaryPolicy{ApprovalThresholdPercent: 20, Currencies: []string{"USD"}})
+	return uc, catRepo, salaryRepo
+}
+
+func asHandler(subject string) context.Context {
+	ctx := requestctx.WithPrincipal(context.Background(), &model.Principal{Subject: subject, Roles: []string{model.RoleHandler}})
+	return requestctx.WithActor(ctx, subject)
+}
+
+func TestSalaryRaiseNeedsAnotherApprover(t *testing.T) {
+	uc, catRepo, _ := newTestCatUsecase(model.Cat{ID: 1, Salary: model.Money{Amount: 100000, Currency: "USD"}, Version: 1})
+	alice, bob := asHandler("handler:alice"), asHandler("handler:bob")
+
+	change := &model.SalaryChange{NewSalary: model.Money{Amount: 200000}}
+	if err := uc.UpdateCatSalary(alice, 1, change); err != nil {
+		t.Fatal(err)
+	}
+	if change.Status != model.SalaryChangePending {
+		t.Fatalf("raise of 100%% is %s, want pending", change.Status)
+	}
+
+	if _, err := uc.ApproveSalaryChange(alice, 1, change.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("requester approving own change: err = %v, want ErrForbidden", err)
+	}
+	if salary := catRepo.cats[1].Salary.Amount; salary != 100000 {
+		t.Errorf("salary = %s after a refused approval, want 1000.00", salary)
+	}
+
+	approved, err := uc.ApproveSalaryChange(bob, 1, change.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Status != model.SalaryChangeApplied || *approved.DecidedBy != "handler:bob" {
+		t.Errorf("approved change = %+v, want applied by handler:bob", approved)
+	}
+	if salary := catRepo.cats[1].Salary.Amount; salary != 200000 {
+		t.Errorf("salary = %s after the approval, want 2000.00", salary)
+	}
+}
+
+func TestSalaryRequesterMayWithdrawChange(t *testing.T) {
+	uc, catRepo, _ := newTestCatUsecase(model.Cat{ID: 1, Salary: model.Money{Amount: 100000, Currency: "USD"}, Version: 1})
+	alice := asHandler("handler:alice")
+
+	change := &model.SalaryChange{NewSalary: model.Money{Amount: 200000}}
+	if err := uc.UpdateCatSalary(alice, 1, change); err != nil {
+		t.Fatal(err)
+	}
+	rejected, err := uc.RejectSalaryChange(alice, 1, change.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rejected.Status != model.SalaryChangeRejected {
+		t.Errorf("status = %s, want rejected", rejected.Status)
+	}
+	if salary := catRepo.cats[1].Salary.Amount; salary != 100000 {
+		t.Errorf("salary = %s after the rejection, want 1000.00", salary)
+	}
+}
+
+func TestCatDeleteChecksIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch []string
+		wantErr error
+	}{
+		{name: "no If-Match"},
+		{name: "current version", ifMatch: []string{model.ETag(1), model.ETag(2)}},
+		{name: "any version", ifMatch: []string{"*"}},
+		{name: "stale version", ifMatch: []string{model.ETag(1)}, wantErr: ErrPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, catRepo, _ := newTestCatUsecase(model.Cat{ID: 1, Version: 2})
+			ctx := asHandler("handler:alice")
+			if tt.ifMatch != nil {
+				ctx = requestctx.WithIfMatch(ctx, tt.ifMatch)
+			}
+
+			err := uc.DeleteCat(ctx, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if _, kept := catRepo.cats[1]; kept != (tt.wantErr != nil) {
+				t.Errorf("cat kept = %t, want %t", kept, tt.wantErr != nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when the requested entity does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when the operation is not allowed in the current state of the entity.
+	ErrConflict = errors.New("conflict")
+	// ErrInvalidInput is returned when the request data does not pass validation.
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrPreconditionFailed is returned when the entity does not match the version the caller expects.
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrUnauthenticated is returned when the caller credentials are missing or not valid.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden is returned when the caller is not allowed to perform the operation.
+	ErrForbidden = errors.New("forbidden")
+)
@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/logger/sl"
+)
+
+// EventStreamFilter selects the events of a live stream. Zero fields match everything.
+type EventStreamFilter struct {
+	MissionID int
+	CatID     int
+	// tenantID is the agency of the subscriber, it is not up to the caller.
+	tenantID int
+}
+
+func (f EventStreamFilter) matches(e model.StreamEvent) bool {
+	if f.tenantID != 0 && e.TenantID != f.tenantID {
+		return false
+	}
+	if f.MissionID != 0 && (e.MissionID == nil || *e.MissionID != f.MissionID) {
+		return false
+	}
+	if f.CatID != 0 && (e.CatID == nil || *e.CatID != f.CatID) {
+		return false
+	}
+	return true
+}
+
+type EventStreamUsecase interface {
+	// Subscribe registers a live subscriber. When lastEventID is set, replay holds the buffered
+	// events after it. The events channel is closed when the subscriber falls too far behind;
+	// cancel must be called once the subscriber is gone.
+	Subscribe(ctx context.Context, filter EventStreamFilter, lastEventID int64) (replay []model.StreamEvent, events <-chan model.StreamEvent, cancel func(), err error)
+}
+
+const (
+	defaultStreamBufferSize = 1000
+	// subscriberQueueSize is how many events a slow subscriber may lag behind before it is dropped.
+	subscriberQueueSize = 64
+)
+
+type streamSubscriber struct {
+	filter EventStreamFilter
+	ch     chan model.StreamEvent
+}
+
+// EventBroker fans domain events out to live subscribers. It learns about new events from
+// the EventListener, so events of every app replica are seen, and keeps the last events in
+// a bounded buffer to replay them to reconnecting subscribers.
+type EventBroker struct {
+	streamRepo domain.EventStreamRepository
+	listener   domain.EventListener
+	bufferSize int
+	logger     *slog.Logger
+
+	mu     sync.Mutex
+	buffer []model.StreamEvent // ordered by ID
+	// seeded is set once the buffer was filled with the latest events. Until then the last
+	// seen event is unknown and catching up would replay the oldest events as new ones.
+	seeded bool
+	subs   map[*streamSubscriber]struct{}
+}
+
+func NewEventBroker(sr domain.EventStreamRepository, listener domain.EventListener, bufferSize int, logger *slog.Logger) *EventBroker {
+	if bufferSize <= 0 {
+		bufferSize = defaultStreamBufferSize
+	}
+	return &EventBroker{
+		streamRepo: sr,
+		listener:   listener,
+		bufferSize: bufferSize,
+		logger:     logger.With("component", "event_broker"),
+		subs:       make(map[*streamSubscriber]struct{}),
+	}
+}
+
+// Run fills the replay buffer and follows new events until ctx is done.
+// Then it ends all subscriptions, so open streams do not hold up the shutdown.
+func (b *EventBroker) Run(ctx context.Context) {
+	defer b.closeSubscribers()
+
+	b.seed(ctx)
+
+	for ctx.Err() == nil {
+		err := b.listener.Listen(ctx, func(eventID int64) {
+			if eventID == 0 {
+				b.catchUp(ctx)
+				return
+			}
+			event, err := b.streamRepo.GetByID(ctx, eventID)
+			if err != nil {
+				b.logger.Error("Failed to load event", "event_id", eventID, sl.Err(err))
+				return
+			}
+			if event != nil {
+				b.add(*event)
+			}
+		})
+		if err != nil {
+			b.logger.Error("Event listener stopped", sl.Err(err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+func (b *EventBroker) Subscribe(ctx context.Context, filter EventStreamFilter, lastEventID int64) ([]model.StreamEvent, <-chan model.StreamEvent, func(), error) {
+	if err := requirePermission(ctx, PermEventsRead); err != nil {
+		return nil, nil, nil, err
+	}
+	filter.tenantID = currentTenant(ctx)
+	sub := &streamSubscriber{filter: filter, ch: make(chan model.StreamEvent, subscriberQueueSize)}
+
+	b.mu.Lock()
+	var replay []model.StreamEvent
+	if lastEventID > 0 {
+		for _, e := range b.buffer {
+			if e.ID > lastEventID && filter.matches(e) {
+				replay = append(replay, e)
+			}
+		}
+	}
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[sub]; ok {
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+	return replay, sub.ch, cancel, nil
+}
+
+func (b *EventBroker) closeSubscribers() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// seed fills the buffer with the latest events without sending them to subscribers.
+// Events added in the meantime are kept.
+func (b *EventBroker) seed(ctx context.Context) {
+	latest, err := b.streamRepo.ListLatest(ctx, b.bufferSize)
+	if err != nil {
+		b.logger.Error("Failed to load recent events", sl.Err(err))
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, e := range latest {
+		b.insert(e)
+	}
+	b.seeded = true
+}
+
+// catchUp loads the events missed while the listener was disconnected.
+func (b *EventBroker) catchUp(ctx context.Context) {
+	b.mu.Lock()
+	seeded := b.seeded
+	var lastID int64
+	if n := len(b.buffer); n > 0 {
+		lastID = b.buffer[n-1].ID
+	}
+	b.mu.Unlock()
+
+	if !seeded {
+		// Loading the recent events failed on startup, so there is no last seen event to continue from.
+		b.seed(ctx)
+		return
+	}
+
+	events, err := b.streamRepo.ListAfter(ctx, lastID, b.bufferSize)
+	if err != nil {
+		b.logger.Error("Failed to catch up on events", sl.Err(err))
+		return
+	}
+	for _, e := range events {
+		b.add(e)
+	}
+}
+
+func (b *EventBroker) add(e model.StreamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.insert(e) {
+		return
+	}
+	for sub := range b.subs {
+		if !sub.filter.matches(e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+			// The subscriber cannot keep up; closing makes it reconnect with Last-Event-ID.
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// insert adds e to the buffer and reports whether it was new. b.mu must be held.
+func (b *EventBroker) insert(e model.StreamEvent) bool {
+	// Transactions may commit out of ID order, keep the buffer sorted and skip duplicates.
+	i, found := slices.BinarySearchFunc(b.buffer, e.ID, func(x model.StreamEvent, id int64) int {
+		return cmp.Compare(x.ID, id)
+	})
+	if found {
+		return false
+	}
+	b.buffer = slices.Insert(b.buffer, i, e)
+	if len(b.buffer) > b.bufferSize {
+		b.buffer = slices.Delete(b.buffer, 0, len(b.buffer)-b.bufferSize)
+	}
+	return true
+}
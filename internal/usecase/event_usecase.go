@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/logger/sl"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
+)
+
+type EventUsecase interface {
+	// Publish writes a domain event to the outbox. It has to be called inside the transaction
+	// of the change, so the event is stored if and only if the change is committed.
+	Publish(ctx context.Context, eventType, aggregateType string, aggregateID int, payload any) error
+}
+
+type eventUsecase struct {
+	outboxRepo domain.OutboxRepository
+}
+
+func NewEventUsecase(or domain.OutboxRepository) EventUsecase {
+	return &eventUsecase{outboxRepo: or}
+}
+
+func (u *eventUsecase) Publish(ctx context.Context, eventType, aggregateType string, aggregateID int, payload any) error {
+	ctx, span := tracing.Start(ctx, "EventUsecase.Publish")
+	defer span.End()
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("events: failed to encode %s payload: %w", eventType, err)
+	}
+	event := &model.DomainEvent{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       b,
+		Actor:         requestctx.Actor(ctx),
+		RequestID:     requestctx.RequestID(ctx),
+	}
+	if err := u.outboxRepo.Add(ctx, event); err != nil {
+		return fmt.Errorf("events: failed to publish %s: %w", eventType, err)
+	}
+	return nil
+}
+
+// DispatcherConfig tunes the outbox dispatcher. Zero values are replaced by defaults.
+type DispatcherConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// MaxBackoff caps the delay between delivery attempts of a failing event.
+	MaxBackoff time.Duration
+}
+
+// EventDispatcher delivers the events of the outbox to the sinks with at-least-once semantics:
+// an event is marked as dispatched only after every sink handled it, otherwise it is retried
+// later for all sinks. Several dispatchers (e.g. app replicas) may run at the same time.
+type EventDispatcher struct {
+	outboxRepo domain.OutboxRepository
+	tx         domain.Transactor
+	sinks      []domain.EventSink
+	cfg        DispatcherConfig
+	logger     *slog.Logger
+}
+
+func NewEventDispatcher(
+	or domain.OutboxRepository,
+	tx domain.Transactor,
+	sinks []domain.EventSink,
+	cfg DispatcherConfig,
+	logger *slog.Logger,
+) *EventDispatcher {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 10 * time.Minute
+	}
+	return &EventDispatcher{
+		outboxRepo: or,
+		tx:         tx,
+		sinks:      sinks,
+		cfg:        cfg,
+		logger:     logger.With("component", "event_dispatcher"),
+	}
+}
+
+// Run polls the outbox until ctx is done.
+func (d *EventDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// Keep draining while full batches come back.
+		for {
+			n, err := d.DispatchBatch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					d.logger.Error("Failed to dispatch events", sl.Err(err))
+				}
+				break
+			}
+			if n < d.cfg.BatchSize {
+				break
+			}
+		}
+	}
+}
+
+// DispatchBatch delivers one batch of due events and returns the number of events claimed.
+func (d *EventDispatcher) DispatchBatch(ctx context.Context) (int, error) {
+	var claimed int
+	err := d.tx.WithinTx(ctx, func(ctx context.Context) error {
+		events, err := d.outboxRepo.ClaimPending(ctx, d.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		claimed = len(events)
+		for _, event := range events {
+			// A savepoint per event undoes what failing sinks wrote and keeps the transaction usable.
+			err := d.tx.WithinTx(ctx, func(ctx context.Context) error {
+				return d.deliver(ctx, event)
+			})
+			if err != nil {
+				retryAt := time.Now().Add(backoff(d.cfg.PollInterval, d.cfg.MaxBackoff, event.Attempts))
+				d.logger.Warn("Event delivery failed",
+					"event_id", event.ID, "type", event.Type, "attempt", event.Attempts+1, sl.Err(err))
+				if err := d.outboxRepo.MarkFailed(ctx, event.ID, err.Error(), retryAt); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := d.outboxRepo.MarkDispatched(ctx, event.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return claimed, err
+}
+
+func (d *EventDispatcher) deliver(ctx context.Context, event model.DomainEvent) error {
+	// Sinks act within the agency of the event.
+	ctx = requestctx.WithTenant(ctx, event.TenantID)
+	for _, sink := range d.sinks {
+		if err := sink.Handle(ctx, event); err != nil {
+			return fmt.Errorf("sink %s: %w", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+// backoff doubles the delay after every failed attempt, starting from base and capped at maxDelay.
+func backoff(base, maxDelay time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 0; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
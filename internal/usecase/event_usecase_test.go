@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// fakeTransactor runs fn without a transaction.
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeOutboxRepo struct {
+	pending    []model.DomainEvent
+	dispatched []int64
+	failed     map[int64]time.Time
+}
+
+func (r *fakeOutboxRepo) Add(context.Context, *model.DomainEvent) error { return nil }
+
+func (r *fakeOutboxRepo) ClaimPending(_ context.Context, limit int) ([]model.DomainEvent, error) {
+	return r.pending[:min(limit, len(r.pending))], nil
+}
+
+func (r *fakeOutboxRepo) MarkDispatched(_ context.Context, id int64) error {
+	r.dispatched = append(r.dispatched, id)
+	return nil
+}
+
+func (r *fakeOutboxRepo) MarkFailed(_ context.Context, id int64, _ string, nextAttemptAt time.Time) error {
+	r.failed[id] = nextAttemptAt
+	return nil
+}
+
+// fakeSink fails the events in fail and records the agency every event was handled within.
+type fakeSink struct {
+	fail    map[int64]bool
+	tenants map[int64]int
+}
+
+func (s *fakeSink) Name() string { return "fake" }
+
+func (s *fakeSink) Handle(ctx context.Context, e model.DomainEvent) error {
+	s.tenants[e.ID], _ = requestctx.Tenant(ctx)
+	if s.fail[e.ID] {
+		return errors.New("sink is down")
+	}
+	return nil
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{3, 8 * time.Second},
+		{6, time.Minute},
+		{100, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := backoff(time.Second, time.Minute, tt.attempts); got != tt.want {
+			t.Errorf("backoff(1s, 1m, %d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+	if got := backoff(time.Hour, time.Minute, 0); got != time.Minute {
+		t.Errorf("backoff(1h, 1m, 0) = %s, want the cap of 1m", got)
+	}
+}
+
+func TestDispatchBatch(t *testing.T) {
+	repo := &fakeOutboxRepo{
+		pending: []model.DomainEvent{
+			{ID: 1, Type: model.EventCatCreated, TenantID: 1},
+			{ID: 2, Type: model.EventMissionCreated, TenantID: 2, Attempts: 2},
+			{ID: 3, Type: model.EventTargetAdded, TenantID: 2},
+		},
+		failed: make(map[int64]time.Time),
+	}
+	sink := &fakeSink{fail: map[int64]bool{2: true}, tenants: make(map[int64]int)}
+	d := NewEventDispatcher(repo, fakeTransactor{}, []domain.EventSink{sink},
+		DispatcherConfig{PollInterval: time.Second, BatchSize: 10, MaxBackoff: time.Minute}, discardLogger)
+
+	start := time.Now()
+	n, err := d.DispatchBatch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("claimed %d events, want 3", n)
+	}
+	if len(repo.dispatched) != 2 || repo.dispatched[0] != 1 || repo.dispatched[1] != 3 {
+		t.Errorf("dispatched %v, want [1 3]", repo.dispatched)
+	}
+	// The third attempt of event 2 is retried after 1s * 2^2.
+	retryAt, ok := repo.failed[2]
+	if !ok {
+		t.Fatal("failed event 2 was not rescheduled")
+	}
+	if delay := retryAt.Sub(start); delay < 4*time.Second || delay > 5*time.Second {
+		t.Errorf("event 2 is retried after %s, want 4s", delay)
+	}
+	for id, want := range map[int64]int{1: 1, 2: 2, 3: 2} {
+		if sink.tenants[id] != want {
+			t.Errorf("event %d was handled within agency %d, want %d", id, sink.tenants[id], want)
+		}
+	}
+}
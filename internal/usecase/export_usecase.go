@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
+)
+
+type ExportUsecase interface {
+	ExportCats(ctx context.Context, filter model.ExportFilter, fn func(model.Cat) error) error
+	ExportMissions(ctx context.Context, filter model.ExportFilter, fn func(model.Mission) error) error
+	ExportAuditEvents(ctx context.Context, filter model.ExportFilter, fn func(model.AuditEvent) error) error
+}
+
+type exportUsecase struct {
+	exportRepo domain.ExportRepository
+}
+
+func NewExportUsecase(er domain.ExportRepository) ExportUsecase {
+	return &exportUsecase{exportRepo: er}
+}
+
+func (u *exportUsecase) ExportCats(ctx context.Context, filter model.ExportFilter, fn func(model.Cat) error) error {
+	ctx, span := tracing.Start(ctx, "ExportUsecase.ExportCats")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermDataExport); err != nil {
+		return err
+	}
+	if err := validateExportFilter(filter); err != nil {
+		return err
+	}
+	return u.exportRepo.StreamCats(ctx, filter, fn)
+}
+
+func (u *exportUsecase) ExportMissions(ctx context.Context, filter model.ExportFilter, fn func(model.Mission) error) error {
+	ctx, span := tracing.Start(ctx, "ExportUsecase.ExportMissions")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermDataExport); err != nil {
+		return err
+	}
+	if err := validateExportFilter(filter); err != nil {
+		return err
+	}
+	return u.exportRepo.StreamMissions(ctx, filter, fn)
+}
+
+func (u *exportUsecase) ExportAuditEvents(ctx context.Context, filter model.ExportFilter, fn func(model.AuditEvent) error) error {
+	ctx, span := tracing.Start(ctx, "ExportUsecase.ExportAuditEvents")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermDataExport); err != nil {
+		return err
+	}
+	if err := validateExportFilter(filter); err != nil {
+		return err
+	}
+	return u.exportRepo.StreamAuditEvents(ctx, filter, fn)
+}
+
+func validateExportFilter(f model.ExportFilter) error {
+	if !f.Since.IsZero() && !f.Until.IsZero() && !f.Since.Before(f.Until) {
+		return fmt.Errorf("since must be before until: %w", ErrInvalidInput)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/infrastructure/catapi"
+)
+
+// DefaultHealthCheckTimeout is used when no timeout is configured.
+const DefaultHealthCheckTimeout = 2 * time.Second
+
+// HealthConfig configures the readiness checks.
+type HealthConfig struct {
+	// Timeout limits every check.
+	Timeout time.Duration
+	// SchemaVersion is the migration version the code needs, the database may be newer.
+	SchemaVersion int
+	// CheckCatAPI makes thecatapi being reachable a condition of readiness.
+	CheckCatAPI bool
+	Build       model.BuildInfo
+}
+
+type HealthUsecase interface {
+	// Readiness checks the dependencies, it fails once the application is shutting down.
+	Readiness(ctx context.Context) *model.Readiness
+	BuildInfo() model.BuildInfo
+	// ShutDown makes readiness fail, so no new requests are routed to the application.
+	ShutDown()
+}
+
+type healthUsecase struct {
+	healthRepo   domain.HealthRepository
+	catAPI       catapi.CatAPI
+	cfg          HealthConfig
+	shuttingDown atomic.Bool
+}
+
+func NewHealthUsecase(hr domain.HealthRepository, catAPI catapi.CatAPI, cfg HealthConfig) HealthUsecase {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultHealthCheckTimeout
+	}
+	return &healthUsecase{healthRepo: hr, catAPI: catAPI, cfg: cfg}
+}
+
+func (u *healthUsecase) Readiness(ctx context.Context) *model.Readiness {
+	checks := map[string]func(context.Context) error{
+		"postgres":   u.healthRepo.Ping,
+		"migrations": u.checkSchema,
+	}
+	if u.cfg.CheckCatAPI {
+		checks["catapi"] = u.catAPI.Ping
+	}
+
+	readiness := &model.Readiness{Status: model.HealthStatusOK}
+	if u.shuttingDown.Load() {
+		readiness.Status = model.HealthStatusFail
+		readiness.Checks = append(readiness.Checks, model.HealthCheck{
+			Name: "shutdown", Status: model.HealthStatusFail, Error: "shutting down",
+		})
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, u.cfg.Timeout)
+			defer cancel()
+
+			result := model.HealthCheck{Name: name, Status: model.HealthStatusOK}
+			if err := check(ctx); err != nil {
+				result.Status, result.Error = model.HealthStatusFail, err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if result.Status != model.HealthStatusOK {
+				readiness.Status = model.HealthStatusFail
+			}
+			readiness.Checks = append(readiness.Checks, result)
+		}()
+	}
+	wg.Wait()
+
+	slices.SortFunc(readiness.Checks, func(a, b model.HealthCheck) int { return cmp.Compare(a.Name, b.Name) })
+	return readiness
+}
+
+// checkSchema checks that the migrations the code needs are applied.
+func (u *healthUsecase) checkSchema(ctx context.Context) error {
+	version, dirty, err := u.healthRepo.SchemaVersion(ctx)
+	switch {
+	case err != nil:
+		return err
+	case dirty:
+		return fmt.Errorf("migration %d failed and has to be fixed manually", version)
+	case version < u.cfg.SchemaVersion:
+		return fmt.Errorf("schema version %d is older than the required %d", version, u.cfg.SchemaVersion)
+	}
+	return nil
+}
+
+func (u *healthUsecase) BuildInfo() model.BuildInfo {
+	return u.cfg.Build
+}
+
+func (u *healthUsecase) ShutDown() {
+	u.shuttingDown.Store(true)
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+)
+
+type fakeHealthRepo struct {
+	pingErr error
+	version int
+	dirty   bool
+}
+
+func (r fakeHealthRepo) Ping(context.Context) error { return r.pingErr }
+
+func (r fakeHealthRepo) SchemaVersion(context.Context) (int, bool, error) {
+	return r.version, r.dirty, nil
+}
+
+// failedChecks returns the names of the failed checks of readiness.
+func failedChecks(readiness *model.Readiness) []string {
+	var failed []string
+	for _, c := range readiness.Checks {
+		if c.Status != model.HealthStatusOK {
+			failed = append(failed, c.Name)
+		}
+	}
+	return failed
+}
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		repo       fakeHealthRepo
+		wantFailed string
+	}{
+		{name: "ready", repo: fakeHealthRepo{version: 18}},
+		{name: "newer schema", repo: fakeHealthRepo{version: 19}},
+		{name: "older schema", repo: fakeHealthRepo{version: 17}, wantFailed: "migrations"},
+		{name: "dirty schema", repo: fakeHealthRepo{version: 18, dirty: true}, wantFailed: "migrations"},
+		{name: "postgres down", repo: fakeHealthRepo{version: 18, pingErr: errors.New("connection refused")}, wantFailed: "postgres"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewHealthUsecase(tt.repo, nil, HealthConfig{SchemaVersion: 18})
+
+			readiness := uc.Readiness(context.Background())
+			failed := failedChecks(readiness)
+			if tt.wantFailed == "" {
+				if readiness.Status != model.HealthStatusOK || len(failed) > 0 {
+					t.Errorf("readiness = %s, failed checks %v, want ok", readiness.Status, failed)
+				}
+				return
+			}
+			if readiness.Status != model.HealthStatusFail || len(failed) != 1 || failed[0] != tt.wantFailed {
+				t.Errorf("readiness = %s, failed checks %v, want %s failing", readiness.Status, failed, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestReadinessFailsAfterShutDown(t *testing.T) {
+	uc := NewHealthUsecase(fakeHealthRepo{version: 18}, nil, HealthConfig{SchemaVersion: 18})
+	if readiness := uc.Readiness(context.Background()); readiness.Status != model.HealthStatusOK {
+		t.Fatalf("readiness before the shutdown = %s, want ok", readiness.Status)
+	}
+
+	uc.ShutDown()
+	readiness := uc.Readiness(context.Background())
+	if failed := failedChecks(readiness); readiness.Status != model.HealthStatusFail || len(failed) != 1 || failed[0] != "shutdown" {
+		t.Errorf("readiness after the shutdown = %s, failed checks %v, want shutdown failing", readiness.Status, failed)
+	}
+}
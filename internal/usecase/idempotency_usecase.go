@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent again with a different request.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+	// ErrIdempotencyInProgress is returned when the request with the same key has not finished yet.
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is still being processed")
+)
+
+const (
+	// DefaultIdempotencyTTL is used when no TTL is configured.
+	DefaultIdempotencyTTL = 24 * time.Hour
+	// DefaultIdempotencyLockTimeout is used when no lock timeout is configured.
+	DefaultIdempotencyLockTimeout = time.Minute
+)
+
+// IdempotencyUsecase makes retried requests return the response of the first attempt.
+// Keys are scoped to the agency and the actor of the request.
+type IdempotencyUsecase interface {
+	// Begin reserves the key for a request with the given fingerprint. It returns the completed
+	// record when the request was already processed, or the new reservation that the caller
+	// completes or releases once it has processed the request.
+	Begin(ctx context.Context, key, fingerprint string) (*model.IdempotencyRecord, error)
+	// Complete stores the response set on the reservation, so it is replayed on retries.
+	Complete(ctx context.Context, rec *model.IdempotencyRecord) error
+	// Release forgets the reservation of a request that failed, so the client can retry it.
+	Release(ctx context.Context, rec *model.IdempotencyRecord) error
+	// PurgeExpired deletes the records older than the TTL.
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type idempotencyUsecase struct {
+	repo domain.IdempotencyRepository
+	ttl  time.Duration
+	// lockTimeout is how long a request holds its key. A retry takes over the key after that,
+	// when the request crashed or timed out without completing or releasing it.
+	lockTimeout time.Duration
+}
+
+func NewIdempotencyUsecase(r domain.IdempotencyRepository, ttl, lockTimeout time.Duration) IdempotencyUsecase {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	if lockTimeout <= 0 {
+		lockTimeout = DefaultIdempotencyLockTimeout
+	}
+	return &idempotencyUsecase{repo: r, ttl: ttl, lockTimeout: lockTimeout}
+}
+
+func (u *idempotencyUsecase) Begin(ctx context.Context, key, fingerprint string) (*model.IdempotencyRecord, error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyUsecase.Begin")
+	defer span.End()
+
+	now := time.Now()
+	rec := &model.IdempotencyRecord{
+		Actor:       requestctx.Actor(ctx),
+		Key:         key,
+		Fingerprint: fingerprint,
+		LockedUntil: now.Add(u.lockTimeout),
+		ExpiresAt:   now.Add(u.ttl),
+	}
+	reserved, err := u.repo.Reserve(ctx, rec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if reserved {
+		return rec, nil
+	}
+
+	existing, err := u.repo.Get(ctx, rec.Actor, key)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		// Released by the first request in the meantime.
+		return nil, ErrIdempotencyInProgress
+	}
+	if existing.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !existing.Completed() {
+		return nil, ErrIdempotencyInProgress
+	}
+	return existing, nil
+}
+
+func (u *idempotencyUsecase) Complete(ctx context.Context, rec *model.IdempotencyRecord) error {
+	ctx, span := tracing.Start(ctx, "IdempotencyUsecase.Complete")
+	defer span.End()
+
+	return u.repo.Complete(ctx, rec)
+}
+
+func (u *idempotencyUsecase) Release(ctx context.Context, rec *model.IdempotencyRecord) error {
+	ctx, span := tracing.Start(ctx, "IdempotencyUsecase.Release")
+	defer span.End()
+
+	return u.repo.Delete(ctx, rec)
+}
+
+func (u *idempotencyUsecase) PurgeExpired(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyUsecase.PurgeExpired")
+	defer span.End()
+
+	return u.repo.DeleteExpired(ctx)
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+)
+
+// fakeIdempotencyRepo keeps the records in memory. Reserve fails while a record of the key
+// exists, unless reserved is set.
+type fakeIdempotencyRepo struct {
+	records map[string]*model.IdempotencyRecord
+	// reserved overrides the outcome of Reserve, e.g. to simulate a release racing with Get.
+	reserved *bool
+}
+
+func newFakeIdempotencyRepo() *fakeIdempotencyRepo {
+	return &fakeIdempotencyRepo{records: make(map[string]*model.IdempotencyRecord)}
+}
+
+func (r *fakeIdempotencyRepo) Reserve(_ context.Context, rec *model.IdempotencyRecord) (bool, error) {
+	if r.reserved != nil {
+		return *r.reserved, nil
+	}
+	if _, ok := r.records[rec.Actor+"/"+rec.Key]; ok {
+		return false, nil
+	}
+	rec.CreatedAt = time.Now()
+	stored := *rec
+	r.records[rec.Actor+"/"+rec.Key] = &stored
+	return true, nil
+}
+
+func (r *fakeIdempotencyRepo) Get(_ context.Context, actor, key string) (*model.IdempotencyRecord, error) {
+	if rec, ok := r.records[actor+"/"+key]; ok {
+		stored := *rec
+		return &stored, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeIdempotencyRepo) Complete(_ context.Context, rec *model.IdempotencyRecord) error {
+	stored := *rec
+	r.records[rec.Actor+"/"+rec.Key] = &stored
+	return nil
+}
+
+func (r *fakeIdempotencyRepo) Delete(_ context.Context, rec *model.IdempotencyRecord) error {
+	delete(r.records, rec.Actor+"/"+rec.Key)
+	return nil
+}
+
+func (r *fakeIdempotencyRepo) DeleteExpired(context.Context) (int64, error) { return 0, nil }
+
+func TestIdempotencyBegin(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeIdempotencyRepo()
+	uc := NewIdempotencyUsecase(repo, time.Hour, time.Minute)
+
+	rec, err := uc.Begin(ctx, "k1", "fp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Completed() {
+		t.Fatal("new reservation is completed")
+	}
+	if d := time.Until(rec.LockedUntil); d <= 0 || d > time.Minute {
+		t.Errorf("key is locked for %s, want the lock timeout of 1m", d)
+	}
+
+	if _, err := uc.Begin(ctx, "k1", "fp"); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Errorf("retry while in progress: err = %v, want ErrIdempotencyInProgress", err)
+	}
+	if _, err := uc.Begin(ctx, "k1", "other"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("different request: err = %v, want ErrIdempotencyKeyReused", err)
+	}
+
+	rec.StatusCode = 201
+	rec.Body = []byte(`{"id":1}`)
+	if err := uc.Complete(ctx, rec); err != nil {
+		t.Fatal(err)
+	}
+	replay, err := uc.Begin(ctx, "k1", "fp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !replay.Completed() || replay.StatusCode != 201 || string(replay.Body) != `{"id":1}` {
+		t.Errorf("retry after completion = %+v, want the stored response", replay)
+	}
+	if _, err := uc.Begin(ctx, "k1", "other"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("different request after completion: err = %v, want ErrIdempotencyKeyReused", err)
+	}
+}
+
+func TestIdempotencyRelease(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeIdempotencyRepo()
+	uc := NewIdempotencyUsecase(repo, 0, 0)
+
+	rec, err := uc.Begin(ctx, "k1", "fp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(rec.ExpiresAt) <= DefaultIdempotencyTTL-time.Minute {
+		t.Errorf("record expires at %s, want the default TTL", rec.ExpiresAt)
+	}
+	if err := uc.Release(ctx, rec); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Begin(ctx, "k1", "fp"); err != nil {
+		t.Errorf("retry after release: %v", err)
+	}
+
+	// The first request released the key between Reserve and Get of the retry.
+	reserved := false
+	repo.reserved = &reserved
+	delete(repo.records, rec.Actor+"/k1")
+	if _, err := uc.Begin(ctx, "k1", "fp"); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Errorf("key released in the meantime: err = %v, want ErrIdempotencyInProgress", err)
+	}
+}
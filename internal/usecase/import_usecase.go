@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/infrastructure/catapi"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
+)
+
+// MaxImportRows limits the number of rows of a single import.
+const MaxImportRows = 10000
+
+// errRolledBack marks rows that were valid but not kept because another row failed in atomic mode.
+var errRolledBack = errors.New("rolled back")
+
+// CatImportRow is a decoded row of a cats import. Err is set when the row could not be decoded.
+type CatImportRow struct {
+	Row int
+	Cat model.Cat
+	Err error
+}
+
+// MissionImportRow is a decoded mission of a missions import. Err is set when it could not be decoded.
+type MissionImportRow struct {
+	Row     int
+	Mission model.Mission
+	Err     error
+}
+
+type ImportUsecase interface {
+	ImportCats(ctx context.Context, mode string, rows []CatImportRow) (*model.ImportReport, error)
+	ImportMissions(ctx context.Context, mode string, rows []MissionImportRow) (*model.ImportReport, error)
+}
+
+type importUsecase struct {
+	catRepo    domain.CatRepository
+	agencyRepo domain.AgencyRepository
+	tx         domain.Transactor
+	catAPI     catapi.CatAPI
+	catUC      CatUsecase
+	missionUC  MissionUsecase
+}
+
+func NewImportUsecase(
+	cr domain.CatRepository,
+	ar domain.AgencyRepository,
+	tx domain.Transactor,
+	catAPI catapi.CatAPI,
+	catUC CatUsecase,
+	missionUC MissionUsecase,
+) ImportUsecase {
+	return &importUsecase{
+		catRepo:    cr,
+		agencyRepo: ar,
+		tx:         tx,
+		catAPI:     catAPI,
+		catUC:      catUC,
+		missionUC:  missionUC,
+	}
+}
+
+// importItem is a row being imported, generic over the created entity.
+type importItem struct {
+	row    int
+	err    error
+	create func(ctx context.Context) (int, error)
+}
+
+func (u *importUsecase) ImportCats(ctx context.Context, mode string, rows []CatImportRow) (*model.ImportReport, error) {
+	ctx, span := tracing.Start(ctx, "ImportUsecase.ImportCats")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermDataImport); err != nil {
+		return nil, err
+	}
+	if err := checkImport(mode, len(rows)); err != nil {
+		return nil, err
+	}
+
+	// Validate breeds against the cached catalogue once for the whole batch.
+	breeds, err := u.catAPI.Breeds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breed catalogue: %w", err)
+	}
+	known := make(map[string]bool, len(breeds))
+	for _, b := range breeds {
+		known[b.Name] = true
+	}
+
+	items := make([]importItem, len(rows))
+	for i := range rows {
+		r := &rows[i]
+		items[i] = importItem{row: r.Row, err: r.Err}
+		if r.Err == nil {
+			items[i].err = validateImportedCat(&r.Cat, known)
+		}
+		items[i].create = func(ctx context.Context) (int, error) {
+			if err := u.catUC.CreateCat(ctx, &r.Cat); err != nil {
+				return 0, err
+			}
+			return r.Cat.ID, nil
+		}
+	}
+	return u.run(ctx, mode, items), nil
+}
+
+func (u *importUsecase) ImportMissions(ctx context.Context, mode string, rows []MissionImportRow) (*model.ImportReport, error) {
+	ctx, span := tracing.Start(ctx, "ImportUsecase.ImportMissions")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermDataImport); err != nil {
+		return nil, err
+	}
+	if err := checkImport(mode, len(rows)); err != nil {
+		return nil, err
+	}
+	agency, err := u.agencyRepo.GetByID(ctx, currentTenant(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if agency == nil {
+		return nil, fmt.Errorf("agency %d: %w", currentTenant(ctx), ErrNotFound)
+	}
+
+	items := make([]importItem, len(rows))
+	for i := range rows {
+		r := &rows[i]
+		items[i] = importItem{row: r.Row, err: r.Err}
+		if r.Err == nil {
+			items[i].err = u.validateImportedMission(ctx, &r.Mission, agency.MaxTargetsPerMission)
+		}
+		items[i].create = func(ctx context.Context) (int, error) {
+			if err := u.missionUC.CreateMission(ctx, &r.Mission); err != nil {
+				return 0, err
+			}
+			return r.Mission.ID, nil
+		}
+	}
+	return u.run(ctx, mode, items), nil
+}
+
+// run creates valid items. In atomic mode nothing is created unless every item is valid and
+// all of them are created in one transaction; in best-effort mode each item is created on its own.
+func (u *importUsecase) run(ctx context.Context, mode string, items []importItem) *model.ImportReport {
+	results := make([]model.ImportRowResult, len(items))
+	failed := false
+	for i, it := range items {
+		results[i].Row = it.row
+		if it.err != nil {
+			failed = true
+		}
+	}
+
+	if mode == model.ImportModeBestEffort {
+		for i, it := range items {
+			if it.err != nil {
+				continue
+			}
+			id, err := it.create(ctx)
+			if err != nil {
+				items[i].err = err
+				continue
+			}
+			results[i].ID = &id
+		}
+		return buildImportReport(mode, items, results)
+	}
+
+	if !failed {
+		err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+			// Every row runs in its own savepoint, so all of them are tried and reported.
+			for i, it := range items {
+				id, err := it.create(ctx)
+				if err != nil {
+					items[i].err = err
+					failed = true
+					continue
+				}
+				results[i].ID = &id
+			}
+			if failed {
+				return errRolledBack
+			}
+			return nil
+		})
+		if err == nil {
+			return buildImportReport(mode, items, results)
+		}
+	}
+
+	for i := range items {
+		results[i].ID = nil
+		if items[i].err == nil {
+			items[i].err = errRolledBack
+		}
+	}
+	return buildImportReport(mode, items, results)
+}
+
+func buildImportReport(mode string, items []importItem, results []model.ImportRowResult) *model.ImportReport {
+	report := &model.ImportReport{Mode: mode, Total: len(items), Results: results}
+	for i, it := range items {
+		switch {
+		case it.err == nil:
+			results[i].Status = model.ImportRowCreated
+			report.Succeeded++
+		case errors.Is(it.err, errRolledBack):
+			results[i].Status = model.ImportRowRolledBack
+			report.Failed++
+		default:
+			results[i].Status = model.ImportRowFailed
+			results[i].Error = it.err.Error()
+			report.Failed++
+		}
+	}
+	return report
+}
+
+func checkImport(mode string, n int) error {
+	if mode != model.ImportModeAtomic && mode != model.ImportModeBestEffort {
+		return fmt.Errorf("unknown import mode %q: %w", mode, ErrInvalidInput)
+	}
+	if n == 0 {
+		return fmt.Errorf("nothing to import: %w", ErrInvalidInput)
+	}
+	if n > MaxImportRows {
+		return fmt.Errorf("too many rows: %d, at most %d are allowed: %w", n, MaxImportRows, ErrInvalidInput)
+	}
+	return nil
+}
+
+func validateImportedCat(cat *model.Cat, knownBreeds map[string]bool) error {
+	if cat.Name == "" {
+		return errors.New("name is required")
+	}
+	if cat.YearsOfExperience < 0 {
+		return errors.New("years_of_experience must not be negative")
+	}
+	if !knownBreeds[cat.Breed] {
+		return fmt.Errorf("breed '%s' is not a valid cat breed", cat.Breed)
+	}
+	if cat.Salary.Amount < 0 {
+		return errors.New("salary must not be negative")
+	}
+	return nil
+}
+
+// validateImportedMission checks the mission against the rules of the agency, maxTargets
+// being its limit of targets per mission.
+func (u *importUsecase) validateImportedMission(ctx context.Context, m *model.Mission, maxTargets int) error {
+	if m.Completed {
+		return errors.New("completed missions cannot be imported")
+	}
+	if len(m.Targets) < 1 || len(m.Targets) > maxTargets {
+		return fmt.Errorf("mission must have from 1 to %d targets, got %d", maxTargets, len(m.Targets))
+	}
+	for i, t := range m.Targets {
+		if t.Name == "" || t.Country == "" {
+			return fmt.Errorf("target %d: name and country are required", i+1)
+		}
+	}
+	if m.CatID != nil {
+		cat, err := u.catRepo.GetByID(ctx, *m.CatID)
+		if err != nil {
+			return err
+		}
+		if cat == nil {
+			return fmt.Errorf("cat %d does not exist", *m.CatID)
+		}
+	}
+	return nil
+}
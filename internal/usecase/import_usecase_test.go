@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/infrastructure/catapi"
+)
+
+type fakeCatAPI struct {
+	catapi.CatAPI
+}
+
+func (fakeCatAPI) Breeds(context.Context) ([]catapi.Breed, error) {
+	return []catapi.Breed{{Name: "Siamese"}, {Name: "Bengal"}}, nil
+}
+
+// fakeCatCreator creates the cats in memory, failing for the names in conflicts like a
+// unique constraint would. Created cats of a failed transaction are dropped.
+type fakeCatCreator struct {
+	CatUsecase
+	created   []string
+	conflicts map[string]bool
+}
+
+func (u *fakeCatCreator) CreateCat(_ context.Context, cat *model.Cat) error {
+	if u.conflicts[cat.Name] {
+		return fmt.Errorf("cat %s already exists: %w", cat.Name, ErrConflict)
+	}
+	u.created = append(u.created, cat.Name)
+	cat.ID = len(u.created)
+	return nil
+}
+
+func (u *fakeCatCreator) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	saved := len(u.created)
+	err := fn(ctx)
+	if err != nil {
+		u.created = u.created[:saved]
+	}
+	return err
+}
+
+func importedCat(row int, name, breed string) CatImportRow {
+	return CatImportRow{Row: row, Cat: model.Cat{Name: name, Breed: breed, Salary: model.Money{Amount: 100000, Currency: "USD"}}}
+}
+
+func TestImportCatsReport(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          string
+		rows          []CatImportRow
+		wantStatuses  []string
+		wantSucceeded int
+		wantCreated   int
+	}{
+		{
+			name:          "atomic imports every row",
+			mode:          model.ImportModeAtomic,
+			rows:          []CatImportRow{importedCat(2, "Tom", "Siamese"), importedCat(3, "Kit", "Bengal")},
+			wantStatuses:  []string{model.ImportRowCreated, model.ImportRowCreated},
+			wantSucceeded: 2,
+			wantCreated:   2,
+		},
+		{
+			name:         "atomic rejects all rows for an invalid one",
+			mode:         model.ImportModeAtomic,
+			rows:         []CatImportRow{importedCat(2, "Tom", "Siamese"), importedCat(3, "Kit", "Dragon")},
+			wantStatuses: []string{model.ImportRowRolledBack, model.ImportRowFailed},
+		},
+		{
+			name:         "atomic rolls back the rows created before a failing one",
+			mode:         model.ImportModeAtomic,
+			rows:         []CatImportRow{importedCat(2, "Tom", "Siamese"), importedCat(3, "Taken", "Bengal")},
+			wantStatuses: []string{model.ImportRowRolledBack, model.ImportRowFailed},
+		},
+		{
+			name: "best effort keeps the valid rows",
+			mode: model.ImportModeBestEffort,
+			rows: []CatImportRow{
+				importedCat(2, "Tom", "Siamese"),
+				importedCat(3, "Kit", "Dragon"),
+				importedCat(4, "Taken", "Bengal"),
+				{Row: 5, Err: errors.New("invalid salary")},
+				importedCat(6, "Kit", "Bengal"),
+			},
+			wantStatuses: []string{
+				model.ImportRowCreated, model.ImportRowFailed, model.ImportRowFailed, model.ImportRowFailed, model.ImportRowCreated,
+			},
+			wantSucceeded: 2,
+			wantCreated:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cats := &fakeCatCreator{conflicts: map[string]bool{"Taken": true}}
+			uc := NewImportUsecase(nil, nil, cats, fakeCatAPI{}, cats, nil)
+
+			report, err := uc.ImportCats(asHandler("handler:alice"), tt.mode, tt.rows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Mode != tt.mode || report.Total != len(tt.rows) || report.Succeeded != tt.wantSucceeded ||
+				report.Failed != len(tt.rows)-tt.wantSucceeded {
+				t.Errorf("report = %d of %d succeeded, %d failed in %s, want %d succeeded",
+					report.Succeeded, report.Total, report.Failed, report.Mode, tt.wantSucceeded)
+			}
+			for i, r := range report.Results {
+				if r.Row != tt.rows[i].Row || r.Status != tt.wantStatuses[i] {
+					t.Errorf("result %d = %+v, want row %d %s", i, r, tt.rows[i].Row, tt.wantStatuses[i])
+				}
+				if (r.Status == model.ImportRowFailed) != (r.Error != "") || (r.Status == model.ImportRowCreated) != (r.ID != nil) {
+					t.Errorf("result %d = %+v, want an error only for failed rows and an id only for created ones", i, r)
+				}
+			}
+			if len(cats.created) != tt.wantCreated {
+				t.Errorf("created cats = %v, want %d", cats.created, tt.wantCreated)
+			}
+		})
+	}
+}
+
+func TestImportRejectsUnknownMode(t *testing.T) {
+	cats := &fakeCatCreator{}
+	uc := NewImportUsecase(nil, nil, cats, fakeCatAPI{}, cats, nil)
+	if _, err := uc.ImportCats(asHandler("handler:alice"), "partial", []CatImportRow{importedCat(2, "Tom", "Siamese")}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("err = %v, want ErrInvalidInput", err)
+	}
+}
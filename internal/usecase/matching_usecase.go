@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/infrastructure/catapi"
+	"github.com/alextotalk/feline-intelligence/internal/lib/logger/sl"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
+)
+
+// Maximum points of every score component, they add up to 100.
+const (
+	experienceWeight     = 30.0
+	countrySuccessWeight = 30.0
+	breedTraitsWeight    = 25.0
+	costWeight           = 15.0
+
+	// Experience and country success are capped so veterans do not dominate the ranking.
+	maxScoredExperience     = 10
+	maxScoredCountrySuccess = 5
+	maxBreedTraitRating     = 5
+)
+
+type MatchingUsecase interface {
+	// Candidates ranks available cats for an unassigned mission, best first.
+	Candidates(ctx context.Context, missionID int) ([]model.Candidate, error)
+	// AutoAssign assigns the best candidate to the mission and returns it.
+	AutoAssign(ctx context.Context, missionID int) (*model.Candidate, error)
+}
+
+type matchingUsecase struct {
+	missionRepo domain.MissionRepository
+	catRepo     domain.CatRepository
+	statsRepo   domain.StatsRepository
+	catAPI      catapi.CatAPI
+	missionUC   MissionUsecase
+}
+
+func NewMatchingUsecase(
+	mr domain.MissionRepository,
+	cr domain.CatRepository,
+	sr domain.StatsRepository,
+	catAPI catapi.CatAPI,
+	missionUC MissionUsecase,
+) MatchingUsecase {
+	return &matchingUsecase{
+		missionRepo: mr,
+		catRepo:     cr,
+		statsRepo:   sr,
+		catAPI:      catAPI,
+		missionUC:   missionUC,
+	}
+}
+
+func (u *matchingUsecase) Candidates(ctx context.Context, missionID int) ([]model.Candidate, error) {
+	ctx, span := tracing.Start(ctx, "MatchingUsecase.Candidates")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermMissionsWrite); err != nil {
+		return nil, err
+	}
+	mission, err := u.missionRepo.GetByID(ctx, missionID)
+	if err != nil {
+		return nil, err
+	}
+	if mission == nil {
+		return nil, fmt.Errorf("mission %d: %w", missionID, ErrNotFound)
+	}
+	if mission.Completed {
+		return nil, fmt.Errorf("mission %d is completed: %w", missionID, ErrConflict)
+	}
+	if mission.CatID != nil {
+		return nil, fmt.Errorf("mission %d is already assigned to cat %d: %w", missionID, *mission.CatID, ErrConflict)
+	}
+
+	cats, err := u.catRepo.GetAvailable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	countries := missionCountries(mission)
+	success, err := u.statsRepo.CompletedTargetsIn(ctx, countries)
+	if err != nil {
+		return nil, err
+	}
+
+	// The breed traits are only a bonus, candidates are still ranked without the catalogue.
+	breeds, err := u.catAPI.Breeds(ctx)
+	catalogueAvailable := err == nil
+	if err != nil {
+		requestctx.Logger(ctx).WarnContext(ctx, "Breed catalogue is unavailable, ranking without breed traits", sl.Err(err))
+	}
+	breedByName := make(map[string]catapi.Breed, len(breeds))
+	for _, b := range breeds {
+		breedByName[b.Name] = b
+	}
+
+	salaryRange := salaryRangeByCurrency(cats)
+
+	candidates := make([]model.Candidate, 0, len(cats))
+	for _, cat := range cats {
+		var c model.Candidate
+		c.Cat = cat
+
+		years := max(0, min(cat.YearsOfExperience, maxScoredExperience))
+		c.Breakdown.Experience = experienceWeight * float64(years) / maxScoredExperience
+		c.Explanations = append(c.Explanations, fmt.Sprintf("%d years of experience", cat.YearsOfExperience))
+
+		completed := success[cat.ID]
+		c.Breakdown.CountrySuccess = countrySuccessWeight * float64(min(completed, maxScoredCountrySuccess)) / maxScoredCountrySuccess
+		if completed > 0 {
+			c.Explanations = append(c.Explanations, fmt.Sprintf("completed %d targets in %s", completed, strings.Join(countries, ", ")))
+		} else {
+			c.Explanations = append(c.Explanations, "no completed targets in the mission countries")
+		}
+
+		if !catalogueAvailable {
+			c.Explanations = append(c.Explanations, "breed catalogue is unavailable, breed traits are not scored")
+		} else if breed, ok := breedByName[cat.Breed]; ok {
+			traits := float64(breed.Intelligence+breed.Adaptability+breed.EnergyLevel) / (3 * maxBreedTraitRating)
+			c.Breakdown.BreedTraits = breedTraitsWeight * traits
+			c.Explanations = append(c.Explanations, fmt.Sprintf(
+				"%s: intelligence %d, adaptability %d, energy %d",
+				breed.Name, breed.Intelligence, breed.Adaptability, breed.EnergyLevel))
+		} else {
+			c.Explanations = append(c.Explanations, fmt.Sprintf("breed %q is not in the catalogue", cat.Breed))
+		}
+
+		r := salaryRange[cat.Salary.Currency]
+		if r[1] > r[0] {
+			c.Breakdown.Cost = costWeight * float64(r[1]-cat.Salary.Amount) / float64(r[1]-r[0])
+		} else {
+			c.Breakdown.Cost = costWeight
+		}
+		c.Explanations = append(c.Explanations, "salary "+cat.Salary.String())
+
+		c.Score = round2(c.Breakdown.Experience + c.Breakdown.CountrySuccess + c.Breakdown.BreedTraits + c.Breakdown.Cost)
+		c.Breakdown = model.CandidateScore{
+			Experience:     round2(c.Breakdown.Experience),
+			CountrySuccess: round2(c.Breakdown.CountrySuccess),
+			BreedTraits:    round2(c.Breakdown.BreedTraits),
+			Cost:           round2(c.Breakdown.Cost),
+		}
+		candidates = append(candidates, c)
+	}
+
+	slices.SortStableFunc(candidates, func(a, b model.Candidate) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return candidates, nil
+}
+
+func (u *matchingUsecase) AutoAssign(ctx context.Context, missionID int) (*model.Candidate, error) {
+	ctx, span := tracing.Start(ctx, "MatchingUsecase.AutoAssign")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermMissionsWrite); err != nil {
+		return nil, err
+	}
+	candidates, err := u.Candidates(ctx, missionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no available cats for mission %d: %w", missionID, ErrConflict)
+	}
+	best := candidates[0]
+	if err := u.missionUC.AssignCatToMission(ctx, missionID, best.Cat.ID); err != nil {
+		return nil, err
+	}
+	return &best, nil
+}
+
+func missionCountries(m *model.Mission) []string {
+	countries := make([]string, 0, len(m.Targets))
+	for _, t := range m.Targets {
+		if !slices.Contains(countries, t.Country) {
+			countries = append(countries, t.Country)
+		}
+	}
+	return countries
+}
+
+// salaryRangeByCurrency returns the minimum and maximum salary of the cats per currency,
+// since salaries in different currencies cannot be compared.
+func salaryRangeByCurrency(cats []model.Cat) map[string][2]model.Amount {
+	ranges := make(map[string][2]model.Amount)
+	for _, c := range cats {
+		r, ok := ranges[c.Salary.Currency]
+		if !ok {
+			r = [2]model.Amount{c.Salary.Amount, c.Salary.Amount}
+		}
+		r[0] = min(r[0], c.Salary.Amount)
+		r[1] = max(r[1], c.Salary.Amount)
+		ranges[c.Salary.Currency] = r
+	}
+	return ranges
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
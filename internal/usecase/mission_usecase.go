@@ -7,6 +7,8 @@ import (
 
 	"github.com/alextotalk/feline-intelligence/internal/domain"
 	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
 )
 
 type MissionUsecase interface {
@@ -16,129 +18,573 @@ type MissionUsecase interface {
 
 	GetMission(ctx context.Context, id int) (*model.Mission, error)
 	ListMissions(ctx context.Context) ([]model.Mission, error)
+	// GetActiveMission returns the mission the cat is working on, nil when it has none.
+	GetActiveMission(ctx context.Context, catID int) (*model.Mission, error)
 	AssignCatToMission(ctx context.Context, missionID, catID int) error
+	UnassignCat(ctx context.Context, missionID int) error
+	ListMissionAssignments(ctx context.Context, missionID int) ([]model.MissionAssignment, error)
+	ListCatAssignments(ctx context.Context, catID int) ([]model.MissionAssignment, error)
 
+	GetTarget(ctx context.Context, id int) (*model.Target, error)
 	AddTarget(ctx context.Context, target *model.Target) error
 	DeleteTarget(ctx context.Context, targetID int) error
 	CompleteTarget(ctx context.Context, targetID int) error
 	UpdateTargetNotes(ctx context.Context, targetID int, newNotes string) error
+	// ExecuteTargetBatch runs the operations in one transaction with all-or-nothing semantics.
+	ExecuteTargetBatch(ctx context.Context, ops []model.TargetOperation) (*model.TargetBatchResult, error)
 }
 
+// MaxTargetBatchSize limits the number of operations of a single targets batch.
+const MaxTargetBatchSize = 100
+
+// errBatchFailed rolls back a targets batch in which an operation failed.
+var errBatchFailed = errors.New("batch failed")
+
 type missionUsecase struct {
-	missionRepo domain.MissionRepository
-	targetRepo  domain.TargetRepository
-	catRepo     domain.CatRepository
+	missionRepo    domain.MissionRepository
+	targetRepo     domain.TargetRepository
+	catRepo        domain.CatRepository
+	assignmentRepo domain.AssignmentRepository
+	tx             domain.Transactor
+	audit          AuditUsecase
+	events         EventUsecase
 }
 
-func NewMissionUsecase(mr domain.MissionRepository, tr domain.TargetRepository, cr domain.CatRepository) MissionUsecase {
+func NewMissionUsecase(
+	mr domain.MissionRepository,
+	tr domain.TargetRepository,
+	cr domain.CatRepository,
+	ar domain.AssignmentRepository,
+	tx domain.Transactor,
+	audit AuditUsecase,
+	events EventUsecase,
+) MissionUsecase {
 	return &missionUsecase{
-		missionRepo: mr,
-		targetRepo:  tr,
-		catRepo:     cr,
+		missionRepo:    mr,
+		targetRepo:     tr,
+		catRepo:        cr,
+		assignmentRepo: ar,
+		tx:             tx,
+		audit:          audit,
+		events:         events,
 	}
 }
 
 func (u *missionUsecase) CreateMission(ctx context.Context, mission *model.Mission) error {
-	if err := u.missionRepo.Create(mission); err != nil {
+	ctx, span := tracing.Start(ctx, "MissionUsecase.CreateMission")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermMissionsWrite); err != nil {
 		return err
 	}
-	for i := range mission.Targets {
-		mission.Targets[i].MissionID = mission.ID
-		if err := u.targetRepo.AddToMission(&mission.Targets[i]); err != nil {
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if mission.CatID != nil {
+			// Cats of other agencies are not visible, so they are reported as missing.
+			cat, err := u.catRepo.GetByID(ctx, *mission.CatID)
+			if err != nil {
+				return err
+			}
+			if cat == nil {
+				return fmt.Errorf("cat %d: %w", *mission.CatID, ErrNotFound)
+			}
+		}
+		if err := u.missionRepo.Create(ctx, mission); err != nil {
 			return err
 		}
-	}
-	return nil
+		for i := range mission.Targets {
+			mission.Targets[i].MissionID = mission.ID
+			if err := u.targetRepo.AddToMission(ctx, &mission.Targets[i]); err != nil {
+				return err
+			}
+		}
+		if mission.CatID != nil && !mission.Completed {
+			if err := u.assignmentRepo.Open(ctx, &model.MissionAssignment{MissionID: mission.ID, CatID: *mission.CatID}); err != nil {
+				return err
+			}
+		}
+		if err := u.audit.Record(ctx, model.AuditActionMissionCreate, model.AuditEntityMission, mission.ID, nil, mission); err != nil {
+			return err
+		}
+		return u.events.Publish(ctx, model.EventMissionCreated, model.AuditEntityMission, mission.ID, mission)
+	})
 }
 
 func (u *missionUsecase) DeleteMission(ctx context.Context, missionID int) error {
-	mission, err := u.missionRepo.GetByID(missionID)
-	if err != nil {
+	ctx, span := tracing.Start(ctx, "MissionUsecase.DeleteMission")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermMissionsWrite); err != nil {
 		return err
 	}
-	if mission.CatID != nil {
-		return fmt.Errorf("cannot delete mission %d: it is assigned to cat", missionID)
-	}
-	return u.missionRepo.Delete(missionID)
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		mission, err := u.getMission(ctx, missionID)
+		if err != nil {
+			return err
+		}
+		if err := checkPrecondition(ctx, model.AuditEntityMission, missionID, mission.Version); err != nil {
+			return err
+		}
+		if mission.CatID != nil {
+			return fmt.Errorf("cannot delete mission %d: it is assigned to cat", missionID)
+		}
+		if err := u.missionRepo.Delete(ctx, missionID); err != nil {
+			return err
+		}
+		if err := u.audit.Record(ctx, model.AuditActionMissionDelete, model.AuditEntityMission, missionID, mission, nil); err != nil {
+			return err
+		}
+		return u.events.Publish(ctx, model.EventMissionDeleted, model.AuditEntityMission, missionID, mission)
+	})
 }
 
 func (u *missionUsecase) CompleteMission(ctx context.Context, missionID int) error {
-	mission, err := u.missionRepo.GetByID(missionID)
-	if err != nil {
+	ctx, span := tracing.Start(ctx, "MissionUsecase.CompleteMission")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermMissionsWrite); err != nil {
 		return err
 	}
-	// check that all goals are completed
-	for _, t := range mission.Targets {
-		if !t.Complete {
-			return fmt.Errorf("target %d is not complete, cannot complete mission %d", t.ID, missionID)
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		mission, err := u.getMission(ctx, missionID)
+		if err != nil {
+			return err
 		}
-	}
-	mission.Completed = true
-	return u.missionRepo.Update(mission)
+		if err := checkPrecondition(ctx, model.AuditEntityMission, missionID, mission.Version); err != nil {
+			return err
+		}
+		// check that all goals are completed
+		for _, t := range mission.Targets {
+			if !t.Complete {
+				return fmt.Errorf("target %d is not complete, cannot complete mission %d", t.ID, missionID)
+			}
+		}
+		before := *mission
+		mission.Completed = true
+		if err := u.missionRepo.Update(ctx, mission); err != nil {
+			return err
+		}
+		if err := u.assignmentRepo.CloseByMission(ctx, missionID, model.UnassignReasonMissionCompleted); err != nil {
+			return err
+		}
+		if err := u.audit.Record(ctx, model.AuditActionMissionComplete, model.AuditEntityMission, missionID, before, mission); err != nil {
+			return err
+		}
+		return u.events.Publish(ctx, model.EventMissionCompleted, model.AuditEntityMission, missionID, mission)
+	})
 }
 
 func (u *missionUsecase) GetMission(ctx context.Context, id int) (*model.Mission, error) {
-	return u.missionRepo.GetByID(id)
+	ctx, span := tracing.Start(ctx, "MissionUsecase.GetMission")
+	defer span.End()
+
+	mission, err := u.missionRepo.GetByID(ctx, id)
+	if err != nil || mission == nil {
+		return mission, err
+	}
+	if err := authorizeMission(ctx, PermMissionsRead, mission); err != nil {
+		return nil, err
+	}
+	return mission, nil
 }
 
 func (u *missionUsecase) ListMissions(ctx context.Context) ([]model.Mission, error) {
-	return u.missionRepo.GetAll()
+	ctx, span := tracing.Start(ctx, "MissionUsecase.ListMissions")
+	defer span.End()
+
+	own, err := authorize(ctx, PermMissionsRead)
+	if err != nil {
+		return nil, err
+	}
+	missions, err := u.missionRepo.GetAll(ctx)
+	if err != nil || own == nil {
+		return missions, err
+	}
+	// Agents only see the missions assigned to them.
+	var owned []model.Mission
+	for _, m := range missions {
+		if m.CatID != nil && *m.CatID == *own {
+			owned = append(owned, m)
+		}
+	}
+	return owned, nil
+}
+
+func (u *missionUsecase) GetActiveMission(ctx context.Context, catID int) (*model.Mission, error) {
+	ctx, span := tracing.Start(ctx, "MissionUsecase.GetActiveMission")
+	defer span.End()
+
+	if err := authorizeCat(ctx, PermMissionsRead, catID); err != nil {
+		return nil, err
+	}
+	return u.missionRepo.GetActiveByCat(ctx, catID)
 }
 
 func (u *missionUsecase) AssignCatToMission(ctx context.Context, missionID, catID int) error {
-	// check if the cat exists
-	cat, err := u.catRepo.GetByID(catID)
-	if err != nil {
+	ctx, span := tracing.Start(ctx, "MissionUsecase.AssignCatToMission")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermMissionsWrite); err != nil {
 		return err
 	}
-	if cat == nil {
-		return errors.New("cat does not exist")
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// check if the cat exists
+		cat, err := u.catRepo.GetByID(ctx, catID)
+		if err != nil {
+			return err
+		}
+		if cat == nil {
+			return fmt.Errorf("cat %d: %w", catID, ErrNotFound)
+		}
+		// check if the mission is completed
+		mission, err := u.getMission(ctx, missionID)
+		if err != nil {
+			return err
+		}
+		if err := checkPrecondition(ctx, model.AuditEntityMission, missionID, mission.Version); err != nil {
+			return err
+		}
+		if mission.Completed {
+			return errors.New("cannot assign cat to a completed mission")
+		}
+		if mission.CatID != nil && *mission.CatID == catID {
+			return nil
+		}
+		if err := u.assignmentRepo.CloseByMission(ctx, missionID, model.UnassignReasonReassigned); err != nil {
+			return err
+		}
+		before := *mission
+		mission.CatID = &catID
+		if err := u.missionRepo.Update(ctx, mission); err != nil {
+			return err
+		}
+		if err := u.assignmentRepo.Open(ctx, &model.MissionAssignment{MissionID: missionID, CatID: catID}); err != nil {
+			return err
+		}
+		if err := u.audit.Record(ctx, model.AuditActionMissionAssignCat, model.AuditEntityMission, missionID, before, mission); err != nil {
+			return err
+		}
+		return u.events.Publish(ctx, model.EventMissionAssigned, model.AuditEntityMission, missionID, mission)
+	})
+}
+
+func (u *missionUsecase) UnassignCat(ctx context.Context, missionID int) error {
+	ctx, span := tracing.Start(ctx, "MissionUsecase.UnassignCat")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermMissionsWrite); err != nil {
+		return err
 	}
-	// check if the mission is completed
-	mission, err := u.missionRepo.GetByID(missionID)
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		mission, err := u.getMission(ctx, missionID)
+		if err != nil {
+			return err
+		}
+		if err := checkPrecondition(ctx, model.AuditEntityMission, missionID, mission.Version); err != nil {
+			return err
+		}
+		if mission.Completed {
+			return fmt.Errorf("cannot unassign cat from completed mission %d: %w", missionID, ErrConflict)
+		}
+		if mission.CatID == nil {
+			return fmt.Errorf("mission %d has no cat assigned: %w", missionID, ErrConflict)
+		}
+		before := *mission
+		mission.CatID = nil
+		if err := u.missionRepo.Update(ctx, mission); err != nil {
+			return err
+		}
+		if err := u.assignmentRepo.CloseByMission(ctx, missionID, model.UnassignReasonUnassigned); err != nil {
+			return err
+		}
+		if err := u.audit.Record(ctx, model.AuditActionMissionUnassignCat, model.AuditEntityMission, missionID, before, mission); err != nil {
+			return err
+		}
+		return u.events.Publish(ctx, model.EventMissionUnassigned, model.AuditEntityMission, missionID, before)
+	})
+}
+
+func (u *missionUsecase) ListMissionAssignments(ctx context.Context, missionID int) ([]model.MissionAssignment, error) {
+	ctx, span := tracing.Start(ctx, "MissionUsecase.ListMissionAssignments")
+	defer span.End()
+
+	mission, err := u.getMission(ctx, missionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if mission.Completed {
-		return errors.New("cannot assign cat to a completed mission")
+	if err := authorizeMission(ctx, PermMissionsRead, mission); err != nil {
+		return nil, err
 	}
-	return u.missionRepo.AssignCat(missionID, catID)
+	return u.assignmentRepo.ListByMission(ctx, missionID)
 }
 
-func (u *missionUsecase) AddTarget(ctx context.Context, target *model.Target) error {
-	mission, err := u.missionRepo.GetByID(target.MissionID)
+func (u *missionUsecase) ListCatAssignments(ctx context.Context, catID int) ([]model.MissionAssignment, error) {
+	ctx, span := tracing.Start(ctx, "MissionUsecase.ListCatAssignments")
+	defer span.End()
+
+	if err := authorizeCat(ctx, PermMissionsRead, catID); err != nil {
+		return nil, err
+	}
+	cat, err := u.catRepo.GetByID(ctx, catID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if mission.Completed {
-		return fmt.Errorf("cannot add target: mission %d is completed", mission.ID)
+	if cat == nil {
+		return nil, fmt.Errorf("cat %d: %w", catID, ErrNotFound)
 	}
-	return u.targetRepo.AddToMission(target)
+	return u.assignmentRepo.ListByCat(ctx, catID)
+}
+
+func (u *missionUsecase) GetTarget(ctx context.Context, id int) (*model.Target, error) {
+	ctx, span := tracing.Start(ctx, "MissionUsecase.GetTarget")
+	defer span.End()
+
+	t, err := u.targetRepo.GetByID(ctx, id)
+	if err != nil || t == nil {
+		return t, err
+	}
+	if err := u.authorizeTarget(ctx, PermMissionsRead, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (u *missionUsecase) AddTarget(ctx context.Context, target *model.Target) error {
+	ctx, span := tracing.Start(ctx, "MissionUsecase.AddTarget")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermMissionsWrite); err != nil {
+		return err
+	}
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		mission, err := u.getMission(ctx, target.MissionID)
+		if err != nil {
+			return err
+		}
+		if mission.Completed {
+			return fmt.Errorf("cannot add target: mission %d is completed", mission.ID)
+		}
+		if err := u.targetRepo.AddToMission(ctx, target); err != nil {
+			return err
+		}
+		if err := u.audit.Record(ctx, model.AuditActionTargetAdd, model.AuditEntityTarget, target.ID, nil, target); err != nil {
+			return err
+		}
+		return u.events.Publish(ctx, model.EventTargetAdded, model.AuditEntityTarget, target.ID, target)
+	})
 }
 
 func (u *missionUsecase) DeleteTarget(ctx context.Context, targetID int) error {
-	return u.targetRepo.Delete(targetID)
+	ctx, span := tracing.Start(ctx, "MissionUsecase.DeleteTarget")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermMissionsWrite); err != nil {
+		return err
+	}
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := u.getTarget(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if err := checkPrecondition(ctx, model.AuditEntityTarget, targetID, before.Version); err != nil {
+			return err
+		}
+		if err := u.targetRepo.Delete(ctx, targetID); err != nil {
+			return err
+		}
+		if err := u.audit.Record(ctx, model.AuditActionTargetDelete, model.AuditEntityTarget, targetID, before, nil); err != nil {
+			return err
+		}
+		return u.events.Publish(ctx, model.EventTargetDeleted, model.AuditEntityTarget, targetID, before)
+	})
 }
 
 func (u *missionUsecase) CompleteTarget(ctx context.Context, targetID int) error {
-	t, err := u.targetRepo.GetByID(targetID)
+	ctx, span := tracing.Start(ctx, "MissionUsecase.CompleteTarget")
+	defer span.End()
+
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		t, err := u.getTarget(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if err := u.authorizeTarget(ctx, PermTargetsUpdate, t); err != nil {
+			return err
+		}
+		if err := checkPrecondition(ctx, model.AuditEntityTarget, targetID, t.Version); err != nil {
+			return err
+		}
+		before := *t
+		t.Complete = true
+		if err := u.targetRepo.Update(ctx, t); err != nil {
+			return err
+		}
+		if err := u.audit.Record(ctx, model.AuditActionTargetComplete, model.AuditEntityTarget, targetID, before, t); err != nil {
+			return err
+		}
+		return u.events.Publish(ctx, model.EventTargetCompleted, model.AuditEntityTarget, targetID, t)
+	})
+}
+
+func (u *missionUsecase) UpdateTargetNotes(ctx context.Context, targetID int, newNotes string) error {
+	ctx, span := tracing.Start(ctx, "MissionUsecase.UpdateTargetNotes")
+	defer span.End()
+
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		t, err := u.getTarget(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if err := u.authorizeTarget(ctx, PermTargetsUpdate, t); err != nil {
+			return err
+		}
+		if err := checkPrecondition(ctx, model.AuditEntityTarget, targetID, t.Version); err != nil {
+			return err
+		}
+		// In the database, triggers check whether it is possible to update Notes.
+		// We can additionally check at the business logic level:
+		if t.Complete {
+			return errors.New("cannot update notes of a completed target")
+		}
+		before := *t
+		t.Notes = newNotes
+		if err := u.targetRepo.Update(ctx, t); err != nil {
+			return err
+		}
+		if err := u.audit.Record(ctx, model.AuditActionTargetUpdateNotes, model.AuditEntityTarget, targetID, before, t); err != nil {
+			return err
+		}
+		return u.events.Publish(ctx, model.EventTargetNotesUpdated, model.AuditEntityTarget, targetID, t)
+	})
+}
+
+func (u *missionUsecase) getMission(ctx context.Context, missionID int) (*model.Mission, error) {
+	mission, err := u.missionRepo.GetByID(ctx, missionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	t.Complete = true
-	return u.targetRepo.Update(t)
+	if mission == nil {
+		return nil, fmt.Errorf("mission %d: %w", missionID, ErrNotFound)
+	}
+	return mission, nil
 }
 
-func (u *missionUsecase) UpdateTargetNotes(ctx context.Context, targetID int, newNotes string) error {
-	t, err := u.targetRepo.GetByID(targetID)
+func (u *missionUsecase) getTarget(ctx context.Context, targetID int) (*model.Target, error) {
+	t, err := u.targetRepo.GetByID(ctx, targetID)
 	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, fmt.Errorf("target %d: %w", targetID, ErrNotFound)
+	}
+	return t, nil
+}
+
+// authorizeTarget checks perm on the mission of the target.
+func (u *missionUsecase) authorizeTarget(ctx context.Context, perm Permission, t *model.Target) error {
+	own, err := authorize(ctx, perm)
+	if err != nil || own == nil {
 		return err
 	}
-	// In the database, triggers check whether it is possible to update Notes.
-	// We can additionally check at the business logic level:
-	if t.Complete {
-		return errors.New("cannot update notes of a completed target")
+	mission, err := u.getMission(ctx, t.MissionID)
+	if err != nil {
+		return err
+	}
+	return authorizeMission(ctx, perm, mission)
+}
+
+func (u *missionUsecase) ExecuteTargetBatch(ctx context.Context, ops []model.TargetOperation) (*model.TargetBatchResult, error) {
+	ctx, span := tracing.Start(ctx, "MissionUsecase.ExecuteTargetBatch")
+	defer span.End()
+
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("batch has no operations: %w", ErrInvalidInput)
+	}
+	if len(ops) > MaxTargetBatchSize {
+		return nil, fmt.Errorf("batch has %d operations, at most %d are allowed: %w", len(ops), MaxTargetBatchSize, ErrInvalidInput)
+	}
+	for i, op := range ops {
+		if err := validateTargetOperation(op); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	result := &model.TargetBatchResult{Results: make([]model.TargetOperationResult, len(ops))}
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		failed := false
+		// Every operation runs in its own savepoint, so the following ones are still tried and reported.
+		for i, op := range ops {
+			r := &result.Results[i]
+			r.Index, r.Op, r.TargetID = i, op.Op, op.TargetID
+
+			targetID, err := u.executeTargetOperation(ctx, op)
+			if err != nil {
+				failed = true
+				r.Status = model.TargetOpStatusFailed
+				r.Error = err.Error()
+				r.RuleViolation = errors.Is(err, domain.ErrRuleViolation)
+				continue
+			}
+			r.Status = model.TargetOpStatusOK
+			r.TargetID = targetID
+		}
+		if failed {
+			return errBatchFailed
+		}
+		return nil
+	})
+
+	switch {
+	case err == nil:
+		result.Committed = true
+	case errors.Is(err, errBatchFailed):
+		for i := range result.Results {
+			if result.Results[i].Status == model.TargetOpStatusOK {
+				result.Results[i].Status = model.TargetOpStatusRolledBack
+			}
+		}
+	default:
+		return nil, err
 	}
-	t.Notes = newNotes
-	return u.targetRepo.Update(t)
+	return result, nil
+}
+
+func (u *missionUsecase) executeTargetOperation(ctx context.Context, op model.TargetOperation) (int, error) {
+	// The If-Match header of the batch request does not apply to its operations, each carries its own version.
+	var etags []string
+	if op.Version != nil {
+		etags = []string{model.ETag(*op.Version)}
+	}
+	ctx = requestctx.WithIfMatch(ctx, etags)
+
+	switch op.Op {
+	case model.TargetOpAdd:
+		target := &model.Target{MissionID: op.MissionID, Name: op.Name, Country: op.Country, Notes: op.Notes}
+		if err := u.AddTarget(ctx, target); err != nil {
+			return 0, err
+		}
+		return target.ID, nil
+	case model.TargetOpComplete:
+		return op.TargetID, u.CompleteTarget(ctx, op.TargetID)
+	case model.TargetOpUpdateNotes:
+		return op.TargetID, u.UpdateTargetNotes(ctx, op.TargetID, op.Notes)
+	case model.TargetOpDelete:
+		return op.TargetID, u.DeleteTarget(ctx, op.TargetID)
+	}
+	return 0, fmt.Errorf("unknown operation %q: %w", op.Op, ErrInvalidInput)
+}
+
+func validateTargetOperation(op model.TargetOperation) error {
+	switch op.Op {
+	case model.TargetOpAdd:
+		if op.MissionID <= 0 || op.Name == "" || op.Country == "" {
+			return fmt.Errorf("add requires mission_id, name and country: %w", ErrInvalidInput)
+		}
+	case model.TargetOpComplete, model.TargetOpUpdateNotes, model.TargetOpDelete:
+		if op.TargetID <= 0 {
+			return fmt.Errorf("%s requires target_id: %w", op.Op, ErrInvalidInput)
+		}
+	default:
+		return fmt.Errorf("unknown operation %q: %w", op.Op, ErrInvalidInput)
+	}
+	return nil
 }
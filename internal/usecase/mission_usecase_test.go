@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+)
+
+type fakeMissionRepo struct {
+	domain.MissionRepository
+	missions map[int]*model.Mission
+}
+
+func (r *fakeMissionRepo) GetByID(_ context.Context, id int) (*model.Mission, error) {
+	if m, ok := r.missions[id]; ok {
+		c := *m
+		return &c, nil
+	}
+	return nil, nil
+}
+
+// fakeTargetRepo keeps the targets in memory and bumps their version on update like the pg repository.
+type fakeTargetRepo struct {
+	domain.TargetRepository
+	targets map[int]model.Target
+	nextID  int
+}
+
+func (r *fakeTargetRepo) GetByID(_ context.Context, id int) (*model.Target, error) {
+	if t, ok := r.targets[id]; ok {
+		return &t, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeTargetRepo) AddToMission(_ context.Context, target *model.Target) error {
+	r.nextID++
+	target.ID, target.Version = r.nextID, 1
+	r.targets[target.ID] = *target
+	return nil
+}
+
+func (r *fakeTargetRepo) Update(_ context.Context, target *model.Target) error {
+	if stored, ok := r.targets[target.ID]; !ok || stored.Version != target.Version {
+		return domain.ErrVersionConflict
+	}
+	target.Version++
+	r.targets[target.ID] = *target
+	return nil
+}
+
+// rollbackTransactor restores the targets when the outermost transaction fails.
+type rollbackTransactor struct {
+	targets *fakeTargetRepo
+	depth   int
+}
+
+func (tx *rollbackTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	saved, nextID := maps.Clone(tx.targets.targets), tx.targets.nextID
+	tx.depth++
+	err := fn(ctx)
+	tx.depth--
+	if err != nil && tx.depth == 0 {
+		tx.targets.targets, tx.targets.nextID = saved, nextID
+	}
+	return err
+}
+
+func newTestMissionUsecase() (MissionUsecase, *fakeTargetRepo) {
+	missionRepo := &fakeMissionRepo{missions: map[int]*model.Mission{1: {ID: 1}}}
+	targetRepo := &fakeTargetRepo{targets: map[int]model.Target{1: {ID: 1, MissionID: 1, Name: "Alpha", Version: 1}}, nextID: 1}
+	uc := NewMissionUsecase(missionRepo, targetRepo, &fakeCatRepo{}, fakeAssignmentRepo{},
+		&rollbackTransactor{targets: targetRepo}, &fakeAudit{}, &fakeEvents{})
+	return uc, targetRepo
+}
+
+func TestTargetBatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		ops           []model.TargetOperation
+		wantCommitted bool
+		wantStatuses  []string
+		wantTargets   int
+		wantComplete  bool
+	}{
+		{
+			name: "all operations succeed",
+			ops: []model.TargetOperation{
+				{Op: model.TargetOpComplete, TargetID: 1},
+				{Op: model.TargetOpAdd, MissionID: 1, Name: "Beta", Country: "Meowland"},
+			},
+			wantCommitted: true,
+			wantStatuses:  []string{model.TargetOpStatusOK, model.TargetOpStatusOK},
+			wantTargets:   2,
+			wantComplete:  true,
+		},
+		{
+			name: "failed operation rolls back the completed ones",
+			ops: []model.TargetOperation{
+				{Op: model.TargetOpComplete, TargetID: 1},
+				{Op: model.TargetOpUpdateNotes, TargetID: 99, Notes: "lost"},
+				{Op: model.TargetOpAdd, MissionID: 1, Name: "Beta", Country: "Meowland"},
+			},
+			wantStatuses: []string{model.TargetOpStatusRolledBack, model.TargetOpStatusFailed, model.TargetOpStatusRolledBack},
+			wantTargets:  1,
+		},
+		{
+			name: "stale version fails the batch",
+			ops: []model.TargetOperation{
+				{Op: model.TargetOpAdd, MissionID: 1, Name: "Beta", Country: "Meowland"},
+				{Op: model.TargetOpComplete, TargetID: 1, Version: ptr(2)},
+			},
+			wantStatuses: []string{model.TargetOpStatusRolledBack, model.TargetOpStatusFailed},
+			wantTargets:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, targetRepo := newTestMissionUsecase()
+
+			result, err := uc.ExecuteTargetBatch(asHandler("handler:alice"), tt.ops)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Committed != tt.wantCommitted {
+				t.Errorf("committed = %t, want %t", result.Committed, tt.wantCommitted)
+			}
+			for i, r := range result.Results {
+				if r.Index != i || r.Status != tt.wantStatuses[i] {
+					t.Errorf("result %d = %+v, want status %s", i, r, tt.wantStatuses[i])
+				}
+			}
+			if len(targetRepo.targets) != tt.wantTargets || targetRepo.targets[1].Complete != tt.wantComplete {
+				t.Errorf("targets after the batch = %+v", targetRepo.targets)
+			}
+		})
+	}
+}
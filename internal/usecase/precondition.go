@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+)
+
+// checkPrecondition verifies the If-Match precondition of the request against the current
+// version of the entity being modified. Requests without a precondition always pass.
+func checkPrecondition(ctx context.Context, entity string, id, version int) error {
+	etags, ok := requestctx.IfMatch(ctx)
+	if !ok {
+		return nil
+	}
+	current := model.ETag(version)
+	for _, etag := range etags {
+		if etag == "*" || etag == current {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %d has version %s: %w", entity, id, current, ErrPreconditionFailed)
+}
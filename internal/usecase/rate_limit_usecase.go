@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/logger/sl"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+)
+
+// RateLimitPolicy configures the limits of every client.
+type RateLimitPolicy struct {
+	// Default limits a client across all routes without a limit of their own.
+	Default model.RateLimit
+	// Routes overrides the limit of routes, keyed by the method and the route pattern,
+	// e.g. "POST /cats". Each of them has a bucket of its own per client.
+	Routes map[string]model.RateLimit
+}
+
+// RateLimitUsecase counts requests of clients against token bucket limits.
+type RateLimitUsecase interface {
+	// Allow counts a request of client to the route (method and route pattern). It returns nil
+	// when the route is not limited. Failures of the store let the request through.
+	Allow(ctx context.Context, client, method, route string) *model.RateLimitDecision
+}
+
+type rateLimitUsecase struct {
+	store  domain.RateLimitStore
+	policy RateLimitPolicy
+}
+
+func NewRateLimitUsecase(store domain.RateLimitStore, policy RateLimitPolicy) RateLimitUsecase {
+	policy.Default = withDefaultBurst(policy.Default)
+	routes := make(map[string]model.RateLimit, len(policy.Routes))
+	for route, limit := range policy.Routes {
+		routes[route] = withDefaultBurst(limit)
+	}
+	policy.Routes = routes
+	return &rateLimitUsecase{store: store, policy: policy}
+}
+
+// withDefaultBurst allows bursts of the whole rate when no burst is configured.
+func withDefaultBurst(limit model.RateLimit) model.RateLimit {
+	if limit.Burst <= 0 {
+		limit.Burst = limit.Requests
+	}
+	return limit
+}
+
+func (u *rateLimitUsecase) Allow(ctx context.Context, client, method, route string) *model.RateLimitDecision {
+	key, limit := client, u.policy.Default
+	if override, ok := u.policy.Routes[method+" "+route]; ok {
+		key, limit = client+" "+method+" "+route, override
+	}
+	if limit.Unlimited() {
+		return nil
+	}
+
+	allowed, tokens, err := u.store.Take(ctx, key, limit)
+	if err != nil {
+		requestctx.Logger(ctx).WarnContext(ctx, "Failed to count request, letting it through", "client", client, sl.Err(err))
+		return nil
+	}
+
+	rate := limit.Rate()
+	decision := &model.RateLimitDecision{
+		Allowed:    allowed,
+		Limit:      limit,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: seconds((float64(limit.Burst) - tokens) / rate),
+	}
+	if !allowed {
+		decision.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	return decision
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
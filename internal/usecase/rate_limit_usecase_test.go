@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+)
+
+// fakeRateLimitStore answers every Take with tokens and err, recording the keys and limits.
+type fakeRateLimitStore struct {
+	allowed bool
+	tokens  float64
+	err     error
+
+	keys   []string
+	limits []model.RateLimit
+}
+
+func (s *fakeRateLimitStore) Take(_ context.Context, key string, limit model.RateLimit) (bool, float64, error) {
+	s.keys = append(s.keys, key)
+	s.limits = append(s.limits, limit)
+	return s.allowed, s.tokens, s.err
+}
+
+func TestRateLimitAllowUsesRouteOverride(t *testing.T) {
+	store := &fakeRateLimitStore{allowed: true, tokens: 4}
+	uc := NewRateLimitUsecase(store, RateLimitPolicy{
+		Default: model.RateLimit{Requests: 600, Per: time.Minute},
+		Routes:  map[string]model.RateLimit{"POST /cats": {Requests: 30, Per: time.Minute, Burst: 10}},
+	})
+
+	uc.Allow(context.Background(), "key:1", "GET", "/cats")
+	uc.Allow(context.Background(), "key:1", "POST", "/cats")
+
+	if want := []string{"key:1", "key:1 POST /cats"}; store.keys[0] != want[0] || store.keys[1] != want[1] {
+		t.Errorf("keys = %q, want %q", store.keys, want)
+	}
+	if store.limits[0].Burst != 600 {
+		t.Errorf("default burst = %d, want the whole rate of 600", store.limits[0].Burst)
+	}
+	if store.limits[1].Burst != 10 {
+		t.Errorf("route burst = %d, want 10", store.limits[1].Burst)
+	}
+}
+
+func TestRateLimitAllowDecision(t *testing.T) {
+	limit := model.RateLimit{Requests: 60, Per: time.Minute, Burst: 10}
+
+	store := &fakeRateLimitStore{allowed: true, tokens: 4.5}
+	d := NewRateLimitUsecase(store, RateLimitPolicy{Default: limit}).Allow(context.Background(), "ip:1.2.3.4", "GET", "/cats")
+	if d == nil || !d.Allowed {
+		t.Fatalf("decision = %+v, want allowed", d)
+	}
+	if d.Remaining != 4 {
+		t.Errorf("Remaining = %d, want 4", d.Remaining)
+	}
+	if d.ResetAfter != 5500*time.Millisecond {
+		t.Errorf("ResetAfter = %s, want 5.5s", d.ResetAfter)
+	}
+	if d.RetryAfter != 0 {
+		t.Errorf("RetryAfter = %s, want 0", d.RetryAfter)
+	}
+
+	store = &fakeRateLimitStore{allowed: false, tokens: 0.25}
+	d = NewRateLimitUsecase(store, RateLimitPolicy{Default: limit}).Allow(context.Background(), "ip:1.2.3.4", "GET", "/cats")
+	if d == nil || d.Allowed {
+		t.Fatalf("decision = %+v, want rejected", d)
+	}
+	if d.RetryAfter != 750*time.Millisecond {
+		t.Errorf("RetryAfter = %s, want 750ms", d.RetryAfter)
+	}
+}
+
+func TestRateLimitAllowLetsThrough(t *testing.T) {
+	store := &fakeRateLimitStore{}
+	uc := NewRateLimitUsecase(store, RateLimitPolicy{
+		Routes: map[string]model.RateLimit{"POST /import/cats": {Requests: 5, Per: time.Minute}},
+	})
+	if d := uc.Allow(context.Background(), "key:1", "GET", "/cats"); d != nil {
+		t.Errorf("route without a limit got decision %+v", d)
+	}
+	if len(store.keys) != 0 {
+		t.Errorf("unlimited route was counted: %q", store.keys)
+	}
+
+	store.err = errors.New("store is down")
+	if d := uc.Allow(context.Background(), "key:1", "POST", "/import/cats"); d != nil {
+		t.Errorf("store failure got decision %+v, want the request let through", d)
+	}
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
+)
+
+type ReportUsecase interface {
+	PayrollReport(ctx context.Context, filter model.PayrollFilter) (*model.PayrollReport, error)
+}
+
+// experienceBand is a range of years of experience, Max < 0 means unbounded.
+type experienceBand struct {
+	Min, Max int
+}
+
+func (b experienceBand) String() string {
+	if b.Max < 0 {
+		return fmt.Sprintf("%d+", b.Min)
+	}
+	return fmt.Sprintf("%d-%d", b.Min, b.Max)
+}
+
+var experienceBands = []experienceBand{{0, 1}, {2, 4}, {5, 9}, {10, -1}}
+
+func experienceBandOf(years int) string {
+	for _, b := range experienceBands {
+		if years >= b.Min && (b.Max < 0 || years <= b.Max) {
+			return b.String()
+		}
+	}
+	return experienceBands[0].String()
+}
+
+type reportUsecase struct {
+	reportRepo domain.ReportRepository
+}
+
+func NewReportUsecase(rr domain.ReportRepository) ReportUsecase {
+	return &reportUsecase{reportRepo: rr}
+}
+
+func (u *reportUsecase) PayrollReport(ctx context.Context, filter model.PayrollFilter) (*model.PayrollReport, error) {
+	ctx, span := tracing.Start(ctx, "ReportUsecase.PayrollReport")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermReportsRead); err != nil {
+		return nil, err
+	}
+	var byBreed, byExperience bool
+	groupBy := make([]string, 0, len(filter.GroupBy))
+	for _, g := range filter.GroupBy {
+		// Entries come from a comma separated list, "breed, experience" is fine as well.
+		if g = strings.TrimSpace(g); g == "" {
+			continue
+		}
+		groupBy = append(groupBy, g)
+		switch g {
+		case model.PayrollGroupBreed:
+			byBreed = true
+		case model.PayrollGroupExperience:
+			byExperience = true
+		default:
+			return nil, fmt.Errorf("unknown payroll grouping %q: %w", g, ErrInvalidInput)
+		}
+	}
+
+	entries, err := u.reportRepo.PayrollEntries(ctx, filter.AsOf)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := newPayrollAggregator()
+	totals := newPayrollAggregator()
+	for _, e := range entries {
+		key := model.PayrollGroup{Currency: e.Salary.Currency}
+		totals.add(key, e.Salary.Amount)
+		if byBreed {
+			key.Breed = e.Breed
+		}
+		if byExperience {
+			key.ExperienceBand = experienceBandOf(e.YearsOfExperience)
+		}
+		groups.add(key, e.Salary.Amount)
+	}
+
+	return &model.PayrollReport{
+		AsOf:        filter.AsOf,
+		GeneratedAt: time.Now(),
+		GroupBy:     groupBy,
+		Groups:      groups.result(),
+		Totals:      totals.result(),
+	}, nil
+}
+
+// payrollAggregator collects salaries per group key (breed, band and currency), keeping insertion order stable.
+type payrollAggregator struct {
+	keys     []model.PayrollGroup
+	salaries map[model.PayrollGroup][]model.Amount
+}
+
+func newPayrollAggregator() *payrollAggregator {
+	return &payrollAggregator{salaries: make(map[model.PayrollGroup][]model.Amount)}
+}
+
+func (a *payrollAggregator) add(key model.PayrollGroup, salary model.Amount) {
+	if _, ok := a.salaries[key]; !ok {
+		a.keys = append(a.keys, key)
+	}
+	a.salaries[key] = append(a.salaries[key], salary)
+}
+
+func (a *payrollAggregator) result() []model.PayrollGroup {
+	groups := make([]model.PayrollGroup, 0, len(a.keys))
+	for _, key := range a.keys {
+		salaries := a.salaries[key]
+		slices.Sort(salaries)
+
+		var total model.Amount
+		for _, s := range salaries {
+			total += s
+		}
+		n := model.Amount(len(salaries))
+
+		g := key
+		g.Headcount = len(salaries)
+		g.Total = total
+		g.Average = divRound(total, n)
+		if len(salaries)%2 == 1 {
+			g.Median = salaries[len(salaries)/2]
+		} else {
+			g.Median = divRound(salaries[len(salaries)/2-1]+salaries[len(salaries)/2], 2)
+		}
+		groups = append(groups, g)
+	}
+
+	slices.SortFunc(groups, func(x, y model.PayrollGroup) int {
+		if x.Breed != y.Breed {
+			return cmp.Compare(x.Breed, y.Breed)
+		}
+		if x.ExperienceBand != y.ExperienceBand {
+			return compareBands(x.ExperienceBand, y.ExperienceBand)
+		}
+		return cmp.Compare(x.Currency, y.Currency)
+	})
+	return groups
+}
+
+// divRound divides rounding half away from zero, so averages stay exact to the cent.
+func divRound(a, n model.Amount) model.Amount {
+	if a < 0 {
+		return -divRound(-a, n)
+	}
+	return (a + n/2) / n
+}
+
+func compareBands(x, y string) int {
+	return slices.IndexFunc(experienceBands, func(b experienceBand) bool { return b.String() == x }) -
+		slices.IndexFunc(experienceBands, func(b experienceBand) bool { return b.String() == y })
+}
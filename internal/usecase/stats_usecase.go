@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
+)
+
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+type StatsUsecase interface {
+	GetCatStats(ctx context.Context, catID int) (*model.CatStats, error)
+	Leaderboard(ctx context.Context, sortBy string, limit int) ([]model.LeaderboardEntry, error)
+}
+
+type statsUsecase struct {
+	statsRepo domain.StatsRepository
+}
+
+func NewStatsUsecase(sr domain.StatsRepository) StatsUsecase {
+	return &statsUsecase{statsRepo: sr}
+}
+
+func (u *statsUsecase) GetCatStats(ctx context.Context, catID int) (*model.CatStats, error) {
+	ctx, span := tracing.Start(ctx, "StatsUsecase.GetCatStats")
+	defer span.End()
+
+	if err := authorizeCat(ctx, PermCatsRead, catID); err != nil {
+		return nil, err
+	}
+	stats, err := u.statsRepo.CatStats(ctx, catID)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		return nil, fmt.Errorf("cat %d: %w", catID, ErrNotFound)
+	}
+	return stats, nil
+}
+
+func (u *statsUsecase) Leaderboard(ctx context.Context, sortBy string, limit int) ([]model.LeaderboardEntry, error) {
+	ctx, span := tracing.Start(ctx, "StatsUsecase.Leaderboard")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermReportsRead); err != nil {
+		return nil, err
+	}
+	switch sortBy {
+	case "":
+		sortBy = model.StatsSortCompletedMissions
+	case model.StatsSortCompletedMissions, model.StatsSortTargetsCompleted,
+		model.StatsSortAvgTimeToComplete, model.StatsSortCountries:
+	default:
+		return nil, fmt.Errorf("unknown sort_by %q: %w", sortBy, ErrInvalidInput)
+	}
+	if limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+	limit = min(limit, maxLeaderboardLimit)
+
+	stats, err := u.statsRepo.Leaderboard(ctx, sortBy, limit)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]model.LeaderboardEntry, len(stats))
+	for i, s := range stats {
+		entries[i] = model.LeaderboardEntry{Rank: i + 1, CatStats: s}
+	}
+	return entries, nil
+}
@@ -0,0 +1,367 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/lib/logger/sl"
+	"github.com/alextotalk/feline-intelligence/internal/lib/requestctx"
+	"github.com/alextotalk/feline-intelligence/internal/lib/tracing"
+)
+
+// WebhookUsecase manages the webhook subscriptions of the agency the request acts within.
+// Subscriptions only receive the events of their agency.
+type WebhookUsecase interface {
+	// CreateSubscription validates and stores the subscription, generating a secret when none is given.
+	// The returned subscription is the only one that carries the secret.
+	CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id int) (*model.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	// UpdateSubscription changes the URL, the event types and the active flag of the subscription.
+	// Reactivating a disabled subscription resets its failure counter.
+	UpdateSubscription(ctx context.Context, sub *model.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id int) error
+	ListDeliveries(ctx context.Context, subscriptionID, limit int) ([]model.WebhookDelivery, error)
+}
+
+const (
+	defaultDeliveriesLimit = 100
+	maxDeliveriesLimit     = 1000
+)
+
+type webhookUsecase struct {
+	webhookRepo domain.WebhookRepository
+}
+
+func NewWebhookUsecase(wr domain.WebhookRepository) WebhookUsecase {
+	return &webhookUsecase{webhookRepo: wr}
+}
+
+func (u *webhookUsecase) CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	ctx, span := tracing.Start(ctx, "WebhookUsecase.CreateSubscription")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermWebhooksManage); err != nil {
+		return err
+	}
+	if err := validateWebhookSubscription(sub); err != nil {
+		return err
+	}
+	if sub.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return err
+		}
+		sub.Secret = secret
+	}
+	sub.Active = true
+	sub.ConsecutiveFailures = 0
+	sub.DisabledAt = nil
+	return u.webhookRepo.Create(ctx, sub)
+}
+
+func (u *webhookUsecase) GetSubscription(ctx context.Context, id int) (*model.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookUsecase.GetSubscription")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermWebhooksManage); err != nil {
+		return nil, err
+	}
+	sub, err := u.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+func (u *webhookUsecase) ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookUsecase.ListSubscriptions")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermWebhooksManage); err != nil {
+		return nil, err
+	}
+	subs, err := u.webhookRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	return subs, nil
+}
+
+func (u *webhookUsecase) UpdateSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	ctx, span := tracing.Start(ctx, "WebhookUsecase.UpdateSubscription")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermWebhooksManage); err != nil {
+		return err
+	}
+	if err := validateWebhookSubscription(sub); err != nil {
+		return err
+	}
+	current, err := u.getSubscription(ctx, sub.ID)
+	if err != nil {
+		return err
+	}
+	current.URL = sub.URL
+	current.EventTypes = sub.EventTypes
+	switch {
+	case sub.Active && !current.Active:
+		current.ConsecutiveFailures = 0
+		current.DisabledAt = nil
+	case !sub.Active && current.Active:
+		now := time.Now()
+		current.DisabledAt = &now
+	}
+	current.Active = sub.Active
+	if err := u.webhookRepo.Update(ctx, current); err != nil {
+		return err
+	}
+	current.Secret = ""
+	*sub = *current
+	return nil
+}
+
+func (u *webhookUsecase) DeleteSubscription(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "WebhookUsecase.DeleteSubscription")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermWebhooksManage); err != nil {
+		return err
+	}
+	if _, err := u.getSubscription(ctx, id); err != nil {
+		return err
+	}
+	return u.webhookRepo.Delete(ctx, id)
+}
+
+func (u *webhookUsecase) ListDeliveries(ctx context.Context, subscriptionID, limit int) ([]model.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookUsecase.ListDeliveries")
+	defer span.End()
+
+	if err := requirePermission(ctx, PermWebhooksManage); err != nil {
+		return nil, err
+	}
+	if _, err := u.getSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultDeliveriesLimit
+	}
+	return u.webhookRepo.ListDeliveries(ctx, subscriptionID, min(limit, maxDeliveriesLimit))
+}
+
+func (u *webhookUsecase) getSubscription(ctx context.Context, id int) (*model.WebhookSubscription, error) {
+	sub, err := u.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, fmt.Errorf("webhook subscription %d: %w", id, ErrNotFound)
+	}
+	return sub, nil
+}
+
+func validateWebhookSubscription(sub *model.WebhookSubscription) error {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http(s) URL: %w", ErrInvalidInput)
+	}
+	for _, t := range sub.EventTypes {
+		if !slices.Contains(model.EventTypes, t) {
+			return fmt.Errorf("unknown event type %q: %w", t, ErrInvalidInput)
+		}
+	}
+	if sub.EventTypes == nil {
+		sub.EventTypes = []string{}
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// WebhookDeliveryConfig tunes the webhook deliverer. Zero values are replaced by defaults.
+type WebhookDeliveryConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// MaxAttempts is the number of attempts after which a delivery is given up.
+	MaxAttempts int
+	// MaxBackoff caps the delay between attempts of a delivery.
+	MaxBackoff time.Duration
+	// DisableAfter is the number of consecutive failed attempts that disables a subscription.
+	DisableAfter int
+	// Lease is how long claimed deliveries are reserved for a deliverer. It must exceed the time
+	// to send a whole batch, deliveries not recorded by then are sent again.
+	Lease time.Duration
+}
+
+// WebhookDeliverer sends the queued webhook deliveries, retrying failed ones with
+// exponential backoff. Several deliverers may run at the same time.
+type WebhookDeliverer struct {
+	webhookRepo domain.WebhookRepository
+	tx          domain.Transactor
+	sender      domain.WebhookSender
+	cfg         WebhookDeliveryConfig
+	logger      *slog.Logger
+}
+
+func NewWebhookDeliverer(
+	wr domain.WebhookRepository,
+	tx domain.Transactor,
+	sender domain.WebhookSender,
+	cfg WebhookDeliveryConfig,
+	logger *slog.Logger,
+) *WebhookDeliverer {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 20
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = time.Hour
+	}
+	if cfg.DisableAfter <= 0 {
+		cfg.DisableAfter = 10
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = 5 * time.Minute
+	}
+	return &WebhookDeliverer{
+		webhookRepo: wr,
+		tx:          tx,
+		sender:      sender,
+		cfg:         cfg,
+		logger:      logger.With("component", "webhook_deliverer"),
+	}
+}
+
+// Run sends due deliveries until ctx is done.
+func (d *WebhookDeliverer) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for {
+			n, err := d.DeliverBatch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					d.logger.Error("Failed to deliver webhooks", sl.Err(err))
+				}
+				break
+			}
+			if n < d.cfg.BatchSize {
+				break
+			}
+		}
+	}
+}
+
+// DeliverBatch sends one batch of due deliveries and returns the number of deliveries claimed.
+// No transaction is open while the endpoints are called: the deliveries are claimed for a lease
+// and the outcome of every attempt is recorded in a transaction of its own.
+func (d *WebhookDeliverer) DeliverBatch(ctx context.Context) (int, error) {
+	deliveries, err := d.webhookRepo.ClaimDueDeliveries(ctx, d.cfg.BatchSize, d.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	subs := make(map[int]*model.WebhookSubscription)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		// The subscription is looked up within the agency of the delivery.
+		ctx := requestctx.WithTenant(ctx, delivery.TenantID)
+		sub, ok := subs[delivery.SubscriptionID]
+		if !ok {
+			if sub, err = d.webhookRepo.GetByID(ctx, delivery.SubscriptionID); err != nil {
+				return len(deliveries), err
+			}
+			subs[delivery.SubscriptionID] = sub
+		}
+		if sub == nil || !sub.Active {
+			// Disabled by a previous delivery of this batch, sent once reactivated and the lease is over.
+			continue
+		}
+		if err := d.attempt(ctx, sub, delivery); err != nil {
+			return len(deliveries), err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt sends the delivery and records the outcome on the delivery and the subscription.
+func (d *WebhookDeliverer) attempt(ctx context.Context, sub *model.WebhookSubscription, delivery *model.WebhookDelivery) error {
+	claimedUntil := delivery.NextAttemptAt
+	statusCode, sendErr := d.sender.Send(ctx, *sub, *delivery)
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = nil
+	if statusCode != 0 {
+		delivery.LastStatusCode = &statusCode
+	}
+
+	if sendErr == nil {
+		delivery.Status = model.WebhookDeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	} else {
+		delivery.LastError = sendErr.Error()
+		if delivery.Attempts >= d.cfg.MaxAttempts {
+			delivery.Status = model.WebhookDeliveryFailed
+		} else {
+			delivery.NextAttemptAt = now.Add(backoff(d.cfg.PollInterval, d.cfg.MaxBackoff, delivery.Attempts))
+		}
+		d.logger.Warn("Webhook delivery failed",
+			"delivery_id", delivery.ID, "subscription_id", sub.ID, "attempt", delivery.Attempts, sl.Err(sendErr))
+	}
+
+	recorded, disabled := false, false
+	err := d.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if recorded, err = d.webhookRepo.UpdateDelivery(ctx, delivery, claimedUntil); err != nil || !recorded {
+			return err
+		}
+		if sendErr == nil {
+			return d.webhookRepo.ResetFailures(ctx, sub.ID)
+		}
+		disabled, err = d.webhookRepo.RecordFailure(ctx, sub.ID, d.cfg.DisableAfter)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		d.logger.Warn("Webhook delivery was claimed again before its outcome was recorded, increase the lease",
+			"delivery_id", delivery.ID, "subscription_id", sub.ID)
+	}
+	if disabled {
+		sub.Active = false
+		d.logger.Warn("Webhook subscription disabled after repeated failures",
+			"subscription_id", sub.ID, "url", sub.URL)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alextotalk/feline-intelligence/internal/domain"
+	"github.com/alextotalk/feline-intelligence/internal/domain/model"
+	"github.com/alextotalk/feline-intelligence/internal/infrastructure/webhook"
+)
+
+// fakeWebhookRepo keeps one subscription and its deliveries in memory, claiming them like
+// the pg repository.
+type fakeWebhookRepo struct {
+	domain.WebhookRepository
+	sub        model.WebhookSubscription
+	deliveries []model.WebhookDelivery
+}
+
+func (r *fakeWebhookRepo) GetByID(_ context.Context, id int) (*model.WebhookSubscription, error) {
+	if id != r.sub.ID {
+		return nil, nil
+	}
+	sub := r.sub
+	return &sub, nil
+}
+
+func (r *fakeWebhookRepo) RecordFailure(_ context.Context, _ int, disableAfter int) (bool, error) {
+	r.sub.ConsecutiveFailures++
+	if r.sub.Active && r.sub.ConsecutiveFailures >= disableAfter {
+		r.sub.Active = false
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *fakeWebhookRepo) ResetFailures(context.Context, int) error {
+	r.sub.ConsecutiveFailures = 0
+	return nil
+}
+
+func (r *fakeWebhookRepo) ClaimDueDeliveries(_ context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	var claimed []model.WebhookDelivery
+	now := time.Now()
+	for i := range r.deliveries {
+		d := &r.deliveries[i]
+		if len(claimed) < limit && r.sub.Active && d.Status == model.WebhookDeliveryPending && !d.NextAttemptAt.After(now) {
+			d.NextAttemptAt = now.Add(lease)
+			claimed = append(claimed, *d)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *fakeWebhookRepo) UpdateDelivery(_ context.Context, delivery *model.WebhookDelivery, claimedUntil time.Time) (bool, error) {
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == delivery.ID && r.deliveries[i].NextAttemptAt.Equal(claimedUntil) {
+			r.deliveries[i] = *delivery
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// makeDue moves the next attempt of every pending delivery to now, as if the backoff had passed.
+func (r *fakeWebhookRepo) makeDue() {
+	for i := range r.deliveries {
+		r.deliveries[i].NextAttemptAt = time.Now()
+	}
+}
+
+// newTestReceiver answers with the status set in status, counting the requests.
+func newTestReceiver(t *testing.T, status *atomic.Int32, requests *atomic.Int32) *httptest.Server {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+func newTestDeliverer(receiver *httptest.Server, cfg WebhookDeliveryConfig, deliveries int) (*WebhookDeliverer, *fakeWebhookRepo) {
+	repo := &fakeWebhookRepo{sub: model.WebhookSubscription{ID: 1, TenantID: 1, URL: receiver.URL, Secret: "s", Active: true}}
+	for i := range deliveries {
+		repo.deliveries = append(repo.deliveries, model.WebhookDelivery{
+			ID: int64(i + 1), SubscriptionID: 1, TenantID: 1, EventType: model.EventCatCreated,
+			Payload: []byte(`{}`), Status: model.WebhookDeliveryPending,
+		})
+	}
+	return NewWebhookDeliverer(repo, fakeTransactor{}, webhook.NewSender(receiver.Client()), cfg, discardLogger), repo
+}
+
+func TestWebhookDeliveryRetriesUntilMaxAttempts(t *testing.T) {
+	var status, requests atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	d, repo := newTestDeliverer(newTestReceiver(t, &status, &requests),
+		WebhookDeliveryConfig{PollInterval: time.Second, MaxAttempts: 3, MaxBackoff: time.Hour, DisableAfter: 100}, 1)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		start := time.Now()
+		if _, err := d.DeliverBatch(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		delivery := repo.deliveries[0]
+		if delivery.Attempts != attempt {
+			t.Fatalf("attempts = %d, want %d", delivery.Attempts, attempt)
+		}
+		if delivery.LastStatusCode == nil || *delivery.LastStatusCode != http.StatusInternalServerError {
+			t.Errorf("attempt %d: last status code = %v, want 500", attempt, delivery.LastStatusCode)
+		}
+		if attempt < 3 {
+			if delivery.Status != model.WebhookDeliveryPending {
+				t.Fatalf("attempt %d: status = %s, want pending", attempt, delivery.Status)
+			}
+			// The attempt n is retried after 1s * 2^n.
+			want := time.Second << attempt
+			if delay := delivery.NextAttemptAt.Sub(start); delay < want || delay > want+time.Second {
+				t.Errorf("attempt %d is retried after %s, want %s", attempt, delay, want)
+			}
+			if n, _ := d.DeliverBatch(context.Background()); n != 0 {
+				t.Errorf("attempt %d: delivery was retried before the backoff passed", attempt)
+			}
+			repo.makeDue()
+		}
+	}
+	if repo.deliveries[0].Status != model.WebhookDeliveryFailed {
+		t.Errorf("status after %d attempts = %s, want failed", repo.deliveries[0].Attempts, repo.deliveries[0].Status)
+	}
+	repo.makeDue()
+	if n, _ := d.DeliverBatch(context.Background()); n != 0 || requests.Load() != 3 {
+		t.Errorf("failed delivery was sent again, %d requests", requests.Load())
+	}
+}
+
+func TestWebhookSubscriptionDisabledAfterConsecutiveFailures(t *testing.T) {
+	var status, requests atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	d, repo := newTestDeliverer(newTestReceiver(t, &status, &requests),
+		WebhookDeliveryConfig{PollInterval: time.Second, MaxAttempts: 10, DisableAfter: 2}, 3)
+
+	if n, err := d.DeliverBatch(context.Background()); err != nil || n != 3 {
+		t.Fatalf("claimed %d deliveries, err = %v, want 3", n, err)
+	}
+	if repo.sub.Active || repo.sub.ConsecutiveFailures != 2 {
+		t.Errorf("subscription active = %t with %d failures, want disabled after 2", repo.sub.Active, repo.sub.ConsecutiveFailures)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("receiver got %d requests, want the third delivery held back", requests.Load())
+	}
+	if third := repo.deliveries[2]; third.Status != model.WebhookDeliveryPending || third.Attempts != 0 {
+		t.Errorf("held back delivery = %s after %d attempts, want pending and untried", third.Status, third.Attempts)
+	}
+}
+
+func TestWebhookSuccessResetsFailures(t *testing.T) {
+	var status, requests atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	d, repo := newTestDeliverer(newTestReceiver(t, &status, &requests),
+		WebhookDeliveryConfig{PollInterval: time.Second, MaxAttempts: 10, DisableAfter: 3}, 1)
+
+	for range 2 {
+		d.DeliverBatch(context.Background())
+		repo.makeDue()
+	}
+	if repo.sub.ConsecutiveFailures != 2 {
+		t.Fatalf("consecutive failures = %d, want 2", repo.sub.ConsecutiveFailures)
+	}
+
+	status.Store(http.StatusOK)
+	if _, err := d.DeliverBatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	delivery := repo.deliveries[0]
+	if delivery.Status != model.WebhookDeliverySucceeded || delivery.DeliveredAt == nil || delivery.LastError != "" {
+		t.Errorf("delivery = %+v, want succeeded", delivery)
+	}
+	if repo.sub.ConsecutiveFailures != 0 || !repo.sub.Active {
+		t.Errorf("subscription active = %t with %d failures, want active with 0", repo.sub.Active, repo.sub.ConsecutiveFailures)
+	}
+}
+
+func TestWebhookOutcomeOfLostClaimIsDropped(t *testing.T) {
+	var status, requests atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	d, repo := newTestDeliverer(newTestReceiver(t, &status, &requests),
+		WebhookDeliveryConfig{PollInterval: time.Second, DisableAfter: 1}, 1)
+
+	// The lease expires while the endpoint is called and another deliverer claims the delivery.
+	d.sender = senderFunc(func(ctx context.Context, sub model.WebhookSubscription, delivery model.WebhookDelivery) (int, error) {
+		repo.deliveries[0].NextAttemptAt = delivery.NextAttemptAt.Add(time.Minute)
+		return webhook.NewSender(http.DefaultClient).Send(ctx, sub, delivery)
+	})
+	if _, err := d.DeliverBatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if repo.deliveries[0].Attempts != 0 {
+		t.Errorf("outcome of a lost claim was recorded: %+v", repo.deliveries[0])
+	}
+	if !repo.sub.Active || repo.sub.ConsecutiveFailures != 0 {
+		t.Error("failure of a lost claim was counted on the subscription")
+	}
+}
+
+type senderFunc func(ctx context.Context, sub model.WebhookSubscription, delivery model.WebhookDelivery) (int, error)
+
+func (f senderFunc) Send(ctx context.Context, sub model.WebhookSubscription, delivery model.WebhookDelivery) (int, error) {
+	return f(ctx, sub, delivery)
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_audit_events_created_at;
+DROP INDEX IF EXISTS idx_audit_events_actor;
+DROP INDEX IF EXISTS idx_audit_events_entity;
+
+DROP TABLE IF EXISTS audit_events;
@@ -0,0 +1,17 @@
+-- Audit log of every mutating operation
+CREATE TABLE audit_events (
+                              id BIGSERIAL PRIMARY KEY,
+                              actor TEXT NOT NULL,
+                              action TEXT NOT NULL,
+                              entity_type TEXT NOT NULL,
+                              entity_id INTEGER NOT NULL,
+                              before JSONB,
+                              after JSONB,
+                              request_id TEXT,
+                              created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Indexes for filtering by entity, actor and time range
+CREATE INDEX idx_audit_events_entity ON audit_events(entity_type, entity_id);
+CREATE INDEX idx_audit_events_actor ON audit_events(actor);
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at);
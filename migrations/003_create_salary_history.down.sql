@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_unique_pending_salary_change;
+DROP INDEX IF EXISTS idx_salary_history_cat_id;
+
+DROP TABLE IF EXISTS salary_history;
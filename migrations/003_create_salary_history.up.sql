@@ -0,0 +1,22 @@
+-- History of cat salary changes, including changes waiting for approval
+CREATE TABLE salary_history (
+                                id SERIAL PRIMARY KEY,
+                                cat_id INTEGER NOT NULL REFERENCES spy_cats(id) ON DELETE CASCADE,
+                                previous_salary NUMERIC(10,2) NOT NULL,
+                                new_salary NUMERIC(10,2) NOT NULL,
+                                reason TEXT NOT NULL DEFAULT '',
+                                status TEXT NOT NULL DEFAULT 'applied'
+                                    CHECK (status IN ('applied', 'pending', 'rejected')),
+                                effective_date DATE NOT NULL DEFAULT CURRENT_DATE,
+                                requested_by TEXT NOT NULL,
+                                decided_by TEXT,
+                                decided_at TIMESTAMPTZ,
+                                created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_salary_history_cat_id ON salary_history(cat_id, effective_date);
+
+-- A cat can only have one salary change waiting for approval
+CREATE UNIQUE INDEX idx_unique_pending_salary_change
+    ON salary_history(cat_id)
+    WHERE status = 'pending';
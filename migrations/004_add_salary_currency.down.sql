@@ -0,0 +1,2 @@
+ALTER TABLE salary_history DROP COLUMN IF EXISTS currency;
+ALTER TABLE spy_cats DROP COLUMN IF EXISTS currency;
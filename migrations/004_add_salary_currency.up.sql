@@ -0,0 +1,8 @@
+-- Currency of cat salaries (ISO 4217 code)
+ALTER TABLE spy_cats
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD'
+        CHECK (currency ~ '^[A-Z]{3}$');
+
+ALTER TABLE salary_history
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD'
+        CHECK (currency ~ '^[A-Z]{3}$');
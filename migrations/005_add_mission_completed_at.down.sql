@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trg_set_mission_completed_at ON missions;
+DROP FUNCTION IF EXISTS set_mission_completed_at();
+
+ALTER TABLE missions DROP COLUMN IF EXISTS completed_at;
@@ -0,0 +1,21 @@
+-- Time when the mission was completed, used for performance statistics.
+-- Missions completed before this migration stay NULL, their completion time is unknown.
+ALTER TABLE missions ADD COLUMN completed_at TIMESTAMPTZ;
+
+-- Trigger to stamp completed_at when the mission becomes completed
+CREATE OR REPLACE FUNCTION set_mission_completed_at()
+RETURNS trigger AS $$
+BEGIN
+    IF NEW.completed AND NOT OLD.completed THEN
+        NEW.completed_at := now();
+    ELSIF NOT NEW.completed THEN
+        NEW.completed_at := NULL;
+END IF;
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_set_mission_completed_at
+    BEFORE UPDATE OF completed ON missions
+    FOR EACH ROW
+    EXECUTE FUNCTION set_mission_completed_at();
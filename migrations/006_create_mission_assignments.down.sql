@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS trg_set_target_completed_at ON targets;
+DROP FUNCTION IF EXISTS set_target_completed_at();
+ALTER TABLE targets DROP COLUMN IF EXISTS completed_at;
+
+DROP INDEX IF EXISTS idx_unique_open_assignment;
+DROP INDEX IF EXISTS idx_mission_assignments_cat_id;
+DROP INDEX IF EXISTS idx_mission_assignments_mission_id;
+
+DROP TABLE IF EXISTS mission_assignments;
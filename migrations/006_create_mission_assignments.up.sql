@@ -0,0 +1,47 @@
+-- History of cats assigned to missions. cat_id has no foreign key on purpose:
+-- the history must survive the deletion of the cat.
+CREATE TABLE mission_assignments (
+                                     id SERIAL PRIMARY KEY,
+                                     mission_id INTEGER NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+                                     cat_id INTEGER NOT NULL,
+                                     assigned_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                     unassigned_at TIMESTAMPTZ,
+                                     reason TEXT
+);
+
+CREATE INDEX idx_mission_assignments_mission_id ON mission_assignments(mission_id);
+CREATE INDEX idx_mission_assignments_cat_id ON mission_assignments(cat_id);
+
+-- A mission can only have one open assignment
+CREATE UNIQUE INDEX idx_unique_open_assignment
+    ON mission_assignments(mission_id)
+    WHERE unassigned_at IS NULL;
+
+-- Backfill current assignments
+INSERT INTO mission_assignments (mission_id, cat_id, assigned_at, unassigned_at, reason)
+SELECT id, cat_id, created_at,
+       CASE WHEN completed THEN COALESCE(completed_at, now()) END,
+       CASE WHEN completed THEN 'mission_completed' END
+FROM missions
+WHERE cat_id IS NOT NULL;
+
+-- Time when the target was completed, to credit the cat assigned at that time.
+-- Targets completed before this migration stay NULL.
+ALTER TABLE targets ADD COLUMN completed_at TIMESTAMPTZ;
+
+CREATE OR REPLACE FUNCTION set_target_completed_at()
+RETURNS trigger AS $$
+BEGIN
+    IF NEW.complete AND (TG_OP = 'INSERT' OR NOT OLD.complete) THEN
+        NEW.completed_at := now();
+    ELSIF NOT NEW.complete THEN
+        NEW.completed_at := NULL;
+END IF;
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_set_target_completed_at
+    BEFORE INSERT OR UPDATE OF complete ON targets
+    FOR EACH ROW
+    EXECUTE FUNCTION set_target_completed_at();
@@ -0,0 +1,3 @@
+ALTER TABLE targets DROP COLUMN IF EXISTS version;
+ALTER TABLE missions DROP COLUMN IF EXISTS version;
+ALTER TABLE spy_cats DROP COLUMN IF EXISTS version;
@@ -0,0 +1,5 @@
+-- Row versions for optimistic concurrency control.
+-- Every UPDATE issued by the application bumps the version and checks the expected one.
+ALTER TABLE spy_cats ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE missions ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE targets ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
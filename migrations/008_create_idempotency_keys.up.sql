@@ -0,0 +1,19 @@
+-- Responses of POST requests sent with an Idempotency-Key header, replayed when the client retries.
+-- status_code is NULL while the first request is still being processed. A retry takes over such a key
+-- after locked_until, when the request that reserved it has crashed or timed out.
+CREATE TABLE idempotency_keys (
+                                  actor TEXT NOT NULL,
+                                  key TEXT NOT NULL,
+                                  fingerprint TEXT NOT NULL,
+                                  status_code INTEGER,
+                                  content_type TEXT,
+                                  response_body BYTEA,
+                                  response_headers JSONB,
+                                  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                  locked_until TIMESTAMPTZ NOT NULL,
+                                  expires_at TIMESTAMPTZ NOT NULL,
+                                  PRIMARY KEY (actor, key)
+);
+
+-- Index to purge expired keys
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1,21 @@
+-- Transactional outbox of domain events. Events are written in the same transaction
+-- as the change and delivered to the sinks by the dispatcher afterwards.
+CREATE TABLE outbox_events (
+                               id BIGSERIAL PRIMARY KEY,
+                               event_type TEXT NOT NULL,
+                               aggregate_type TEXT NOT NULL,
+                               aggregate_id INTEGER NOT NULL,
+                               payload JSONB NOT NULL,
+                               actor TEXT NOT NULL,
+                               request_id TEXT,
+                               occurred_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                               attempts INTEGER NOT NULL DEFAULT 0,
+                               next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                               last_error TEXT,
+                               dispatched_at TIMESTAMPTZ
+);
+
+-- Index for the dispatcher to find events waiting for delivery
+CREATE INDEX idx_outbox_events_pending
+    ON outbox_events(next_attempt_at)
+    WHERE dispatched_at IS NULL;
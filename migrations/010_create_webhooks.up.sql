@@ -0,0 +1,36 @@
+-- Webhook subscriptions. An empty event_types array subscribes to all events.
+CREATE TABLE webhook_subscriptions (
+                                       id SERIAL PRIMARY KEY,
+                                       url TEXT NOT NULL,
+                                       secret TEXT NOT NULL,
+                                       event_types TEXT[] NOT NULL DEFAULT '{}',
+                                       active BOOLEAN NOT NULL DEFAULT TRUE,
+                                       consecutive_failures INTEGER NOT NULL DEFAULT 0,
+                                       disabled_at TIMESTAMPTZ,
+                                       created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Delivery log: one row per event and subscription, updated after every attempt
+CREATE TABLE webhook_deliveries (
+                                    id BIGSERIAL PRIMARY KEY,
+                                    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+                                    event_id BIGINT NOT NULL,
+                                    event_type TEXT NOT NULL,
+                                    payload JSONB NOT NULL,
+                                    status TEXT NOT NULL DEFAULT 'pending',
+                                    attempts INTEGER NOT NULL DEFAULT 0,
+                                    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                    last_status_code INTEGER,
+                                    last_error TEXT,
+                                    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                    delivered_at TIMESTAMPTZ
+);
+
+-- The outbox delivers at-least-once, an event is queued only once per subscription
+CREATE UNIQUE INDEX idx_webhook_deliveries_event
+    ON webhook_deliveries(subscription_id, event_id);
+
+-- Index for the deliverer to find due deliveries
+CREATE INDEX idx_webhook_deliveries_pending
+    ON webhook_deliveries(next_attempt_at)
+    WHERE status = 'pending';
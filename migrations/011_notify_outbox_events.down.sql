@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS trg_notify_outbox_event ON outbox_events;
+DROP FUNCTION IF EXISTS notify_outbox_event();
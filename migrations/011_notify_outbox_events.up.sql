@@ -0,0 +1,14 @@
+-- Notify listeners (the live event stream of every app replica) about new domain events.
+-- Notifications are sent on commit, so rolled back changes are never announced.
+CREATE OR REPLACE FUNCTION notify_outbox_event()
+RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('domain_events', NEW.id::text);
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_notify_outbox_event
+    AFTER INSERT ON outbox_events
+    FOR EACH ROW
+    EXECUTE FUNCTION notify_outbox_event();
@@ -0,0 +1,15 @@
+-- API keys. Only the SHA-256 hash of a key is stored, the key itself is shown once on issue.
+CREATE TABLE api_keys (
+                          id SERIAL PRIMARY KEY,
+                          name TEXT NOT NULL,
+                          prefix TEXT NOT NULL,
+                          key_hash TEXT NOT NULL,
+                          subject TEXT NOT NULL,
+                          created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                          last_used_at TIMESTAMPTZ,
+                          expires_at TIMESTAMPTZ,
+                          revoked_at TIMESTAMPTZ
+);
+
+-- Keys are looked up by their hash on every request
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys(key_hash);
@@ -0,0 +1,4 @@
+-- Roles of API keys. Keys issued before roles existed get none and have to be reissued.
+-- cat_id is the cat an agent key acts as.
+ALTER TABLE api_keys ADD COLUMN roles TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE api_keys ADD COLUMN cat_id INTEGER REFERENCES spy_cats(id) ON DELETE CASCADE;
@@ -0,0 +1,52 @@
+-- Restore the fixed limit of 3 targets per mission
+CREATE OR REPLACE FUNCTION check_max_targets()
+RETURNS trigger AS $$
+DECLARE
+target_count INTEGER;
+    mission_completed BOOLEAN;
+BEGIN
+SELECT count(*) INTO target_count FROM targets WHERE mission_id = NEW.mission_id;
+SELECT completed INTO mission_completed FROM missions WHERE id = NEW.mission_id;
+
+IF mission_completed THEN
+        RAISE EXCEPTION 'Cannot add target to mission % because the mission is already completed', NEW.mission_id;
+END IF;
+
+    IF target_count >= 3 THEN
+        RAISE EXCEPTION 'Mission % already has maximum number of targets (3)', NEW.mission_id;
+END IF;
+
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_webhook_subscriptions_tenant_id;
+DROP INDEX IF EXISTS idx_audit_events_tenant_id;
+DROP INDEX IF EXISTS idx_mission_assignments_tenant_id;
+DROP INDEX IF EXISTS idx_salary_history_tenant_id;
+DROP INDEX IF EXISTS idx_targets_tenant_id;
+DROP INDEX IF EXISTS idx_missions_tenant_id;
+DROP INDEX IF EXISTS idx_spy_cats_tenant_id;
+
+-- Keys that only differ by agency cannot be kept
+DELETE FROM idempotency_keys k
+WHERE EXISTS (
+    SELECT 1 FROM idempotency_keys o
+    WHERE o.actor = k.actor AND o.key = k.key AND o.tenant_id < k.tenant_id
+);
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (actor, key);
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS tenant_id;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE webhook_subscriptions DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE mission_assignments DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE salary_history DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE targets DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE missions DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE spy_cats DROP COLUMN IF EXISTS tenant_id;
+
+DROP TABLE IF EXISTS agencies;
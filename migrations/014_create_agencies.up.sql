@@ -0,0 +1,76 @@
+-- Agencies (tenants). Every cat, mission and target, with its history, events and webhooks,
+-- belongs to exactly one agency, the data that existed before belongs to the default agency.
+CREATE TABLE agencies (
+                          id SERIAL PRIMARY KEY,
+                          name TEXT NOT NULL UNIQUE,
+                          max_targets_per_mission INTEGER NOT NULL DEFAULT 3
+                              CHECK (max_targets_per_mission BETWEEN 1 AND 100),
+                          created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO agencies (id, name) VALUES (1, 'Default agency');
+SELECT setval('agencies_id_seq', (SELECT max(id) FROM agencies));
+
+ALTER TABLE spy_cats ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES agencies(id);
+ALTER TABLE missions ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES agencies(id);
+ALTER TABLE targets ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES agencies(id);
+ALTER TABLE salary_history ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES agencies(id);
+ALTER TABLE mission_assignments ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES agencies(id);
+ALTER TABLE audit_events ADD COLUMN tenant_id INTEGER DEFAULT 1 REFERENCES agencies(id);
+ALTER TABLE outbox_events ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES agencies(id);
+ALTER TABLE webhook_subscriptions ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES agencies(id) ON DELETE CASCADE;
+ALTER TABLE webhook_deliveries ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES agencies(id) ON DELETE CASCADE;
+
+-- The application always sets the agency, the defaults only backfilled the existing rows
+ALTER TABLE spy_cats ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE missions ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE targets ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE salary_history ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE mission_assignments ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE audit_events ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE outbox_events ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE webhook_subscriptions ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE webhook_deliveries ALTER COLUMN tenant_id DROP DEFAULT;
+
+-- Idempotency keys are unique per agency, a platform-wide caller may reuse a key in another agency
+ALTER TABLE idempotency_keys ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES agencies(id) ON DELETE CASCADE;
+ALTER TABLE idempotency_keys ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (tenant_id, actor, key);
+
+-- API keys bound to an agency only act within it, keys without one are platform-wide
+ALTER TABLE api_keys ADD COLUMN tenant_id INTEGER REFERENCES agencies(id) ON DELETE CASCADE;
+
+-- Every query is scoped by the agency
+CREATE INDEX idx_spy_cats_tenant_id ON spy_cats(tenant_id);
+CREATE INDEX idx_missions_tenant_id ON missions(tenant_id);
+CREATE INDEX idx_targets_tenant_id ON targets(tenant_id);
+CREATE INDEX idx_salary_history_tenant_id ON salary_history(tenant_id);
+CREATE INDEX idx_mission_assignments_tenant_id ON mission_assignments(tenant_id);
+CREATE INDEX idx_audit_events_tenant_id ON audit_events(tenant_id, created_at);
+CREATE INDEX idx_webhook_subscriptions_tenant_id ON webhook_subscriptions(tenant_id);
+
+-- The maximum number of targets per mission is configured per agency
+CREATE OR REPLACE FUNCTION check_max_targets()
+RETURNS trigger AS $$
+DECLARE
+target_count INTEGER;
+    mission_completed BOOLEAN;
+    max_targets INTEGER;
+BEGIN
+SELECT count(*) INTO target_count FROM targets WHERE mission_id = NEW.mission_id;
+SELECT m.completed, a.max_targets_per_mission INTO mission_completed, max_targets
+FROM missions m JOIN agencies a ON a.id = m.tenant_id
+WHERE m.id = NEW.mission_id;
+
+IF mission_completed THEN
+        RAISE EXCEPTION 'Cannot add target to mission % because the mission is already completed', NEW.mission_id;
+END IF;
+
+    IF target_count >= max_targets THEN
+        RAISE EXCEPTION 'Mission % already has maximum number of targets (%)', NEW.mission_id, max_targets;
+END IF;
+
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,13 @@
+DROP POLICY IF EXISTS tenant_isolation ON targets;
+ALTER TABLE targets NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE targets DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON missions;
+ALTER TABLE missions NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE missions DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON spy_cats;
+ALTER TABLE spy_cats NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE spy_cats DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS tenant_visible(INTEGER);
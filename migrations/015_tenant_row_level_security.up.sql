@@ -0,0 +1,25 @@
+-- Row-level security on the agency data. A session only sees the rows of the agency in
+-- app.tenant_id, so Postgres hides the rows of other agencies even from a query that forgot
+-- to filter, and a session without an agency sees nothing. Sessions that span agencies
+-- (platform jobs, migrations, maintenance) set app.all_tenants to 'on'. Without
+-- database.row_level_security the application sets it on every connection.
+CREATE OR REPLACE FUNCTION tenant_visible(row_tenant_id INTEGER)
+RETURNS boolean AS $$
+    SELECT COALESCE(current_setting('app.all_tenants', true) = 'on', false)
+        OR COALESCE(current_setting('app.tenant_id', true) = row_tenant_id::text, false);
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE spy_cats ENABLE ROW LEVEL SECURITY;
+ALTER TABLE spy_cats FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON spy_cats
+    USING (tenant_visible(tenant_id)) WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE missions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE missions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON missions
+    USING (tenant_visible(tenant_id)) WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE targets ENABLE ROW LEVEL SECURITY;
+ALTER TABLE targets FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON targets
+    USING (tenant_visible(tenant_id)) WITH CHECK (tenant_visible(tenant_id));
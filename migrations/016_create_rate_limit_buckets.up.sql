@@ -0,0 +1,11 @@
+-- Token buckets of rate limits shared by all replicas. The counters are cheap to lose,
+-- so the table is not written to the WAL.
+CREATE UNLOGGED TABLE rate_limit_buckets (
+                          key TEXT PRIMARY KEY,
+                          tokens DOUBLE PRECISION NOT NULL,
+                          updated_at TIMESTAMPTZ NOT NULL,
+                          full_at TIMESTAMPTZ NOT NULL
+);
+
+-- Buckets that filled up again are purged
+CREATE INDEX idx_rate_limit_buckets_full_at ON rate_limit_buckets(full_at);
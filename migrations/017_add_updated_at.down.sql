@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS trg_targets_updated_at ON targets;
+DROP TRIGGER IF EXISTS trg_missions_updated_at ON missions;
+DROP TRIGGER IF EXISTS trg_spy_cats_updated_at ON spy_cats;
+DROP FUNCTION IF EXISTS set_updated_at();
+
+DROP INDEX IF EXISTS idx_targets_updated_at;
+DROP INDEX IF EXISTS idx_missions_updated_at;
+DROP INDEX IF EXISTS idx_spy_cats_updated_at;
+
+ALTER TABLE targets DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE missions DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE spy_cats DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,44 @@
+-- Time of the last modification, so incremental exports also select rows changed after they were created.
+-- Existing rows get their creation time, their modification time is unknown.
+-- The backfill covers every agency, see tenant_visible()
+SELECT set_config('app.all_tenants', 'on', true);
+
+ALTER TABLE spy_cats ADD COLUMN updated_at TIMESTAMPTZ;
+ALTER TABLE missions ADD COLUMN updated_at TIMESTAMPTZ;
+ALTER TABLE targets ADD COLUMN updated_at TIMESTAMPTZ;
+
+UPDATE spy_cats SET updated_at = COALESCE(created_at, now());
+UPDATE missions SET updated_at = COALESCE(created_at, now());
+UPDATE targets SET updated_at = COALESCE(created_at, now());
+
+ALTER TABLE spy_cats ALTER COLUMN updated_at SET DEFAULT now(), ALTER COLUMN updated_at SET NOT NULL;
+ALTER TABLE missions ALTER COLUMN updated_at SET DEFAULT now(), ALTER COLUMN updated_at SET NOT NULL;
+ALTER TABLE targets ALTER COLUMN updated_at SET DEFAULT now(), ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE INDEX idx_spy_cats_updated_at ON spy_cats(tenant_id, updated_at);
+CREATE INDEX idx_missions_updated_at ON missions(tenant_id, updated_at);
+CREATE INDEX idx_targets_updated_at ON targets(mission_id, updated_at);
+
+-- Trigger to stamp updated_at on every update
+CREATE OR REPLACE FUNCTION set_updated_at()
+RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at := now();
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_spy_cats_updated_at
+    BEFORE UPDATE ON spy_cats
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER trg_missions_updated_at
+    BEFORE UPDATE ON missions
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER trg_targets_updated_at
+    BEFORE UPDATE ON targets
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,27 @@
+DROP POLICY IF EXISTS tenant_isolation ON idempotency_keys;
+ALTER TABLE idempotency_keys NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE idempotency_keys DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON webhook_deliveries;
+ALTER TABLE webhook_deliveries NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON webhook_subscriptions;
+ALTER TABLE webhook_subscriptions NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE webhook_subscriptions DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON outbox_events;
+ALTER TABLE outbox_events NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE outbox_events DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON audit_events;
+ALTER TABLE audit_events NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE audit_events DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON mission_assignments;
+ALTER TABLE mission_assignments NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE mission_assignments DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON salary_history;
+ALTER TABLE salary_history NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE salary_history DISABLE ROW LEVEL SECURITY;
@@ -0,0 +1,38 @@
+-- Row-level security on the remaining agency data, with the policy of migration 015: the history
+-- of cats and missions, the audit log, the outbox, webhooks and idempotency keys. Agencies,
+-- API keys (read to authenticate, before the agency is known) and rate limit buckets
+-- stay unrestricted.
+ALTER TABLE salary_history ENABLE ROW LEVEL SECURITY;
+ALTER TABLE salary_history FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON salary_history
+    USING (tenant_visible(tenant_id)) WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE mission_assignments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE mission_assignments FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON mission_assignments
+    USING (tenant_visible(tenant_id)) WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE audit_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_events FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON audit_events
+    USING (tenant_visible(tenant_id)) WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE outbox_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE outbox_events FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON outbox_events
+    USING (tenant_visible(tenant_id)) WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE webhook_subscriptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_subscriptions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON webhook_subscriptions
+    USING (tenant_visible(tenant_id)) WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE webhook_deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON webhook_deliveries
+    USING (tenant_visible(tenant_id)) WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE idempotency_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE idempotency_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON idempotency_keys
+    USING (tenant_visible(tenant_id)) WITH CHECK (tenant_visible(tenant_id));